@@ -0,0 +1,83 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const anthropicMessagesURL = "https://api.anthropic.com/v1/messages"
+
+type anthropicSummarizer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func newAnthropicSummarizer(apiKey string) (*anthropicSummarizer, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEYが設定されていません")
+	}
+	return &anthropicSummarizer{apiKey: apiKey, model: "claude-3-5-haiku-20241022", client: http.DefaultClient}, nil
+}
+
+type anthropicMessagesRequest struct {
+	Model     string              `json:"model"`
+	MaxTokens int                 `json:"max_tokens"`
+	Messages  []anthropicMessage  `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (s *anthropicSummarizer) Summarize(ctx context.Context, in Input) (Summary, error) {
+	reqBody := anthropicMessagesRequest{
+		Model:     s.model,
+		MaxTokens: 1024,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt(in)},
+		},
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicMessagesURL, bytes.NewReader(data))
+	if err != nil {
+		return Summary{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", s.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Summary{}, fmt.Errorf("Anthropic APIリクエストエラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Summary{}, fmt.Errorf("Anthropic APIエラー: status=%d", resp.StatusCode)
+	}
+
+	var msgResp anthropicMessagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msgResp); err != nil {
+		return Summary{}, fmt.Errorf("Anthropicレスポンスの解析エラー: %v", err)
+	}
+	if len(msgResp.Content) == 0 {
+		return Summary{}, fmt.Errorf("Anthropicからの有効なレスポンスがありません")
+	}
+
+	return parseSummaryJSON(msgResp.Content[0].Text)
+}
@@ -0,0 +1,81 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const geminiGenerateContentURLFormat = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s"
+
+type geminiSummarizer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func newGeminiSummarizer(apiKey string) (*geminiSummarizer, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEYが設定されていません")
+	}
+	return &geminiSummarizer{apiKey: apiKey, model: "gemini-1.5-flash", client: http.DefaultClient}, nil
+}
+
+type geminiGenerateRequest struct {
+	Contents []geminiContent `json:"contents"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerateResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+}
+
+func (s *geminiSummarizer) Summarize(ctx context.Context, in Input) (Summary, error) {
+	reqBody := geminiGenerateRequest{
+		Contents: []geminiContent{
+			{Parts: []geminiPart{{Text: prompt(in)}}},
+		},
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	url := fmt.Sprintf(geminiGenerateContentURLFormat, s.model, s.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return Summary{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Summary{}, fmt.Errorf("Gemini APIリクエストエラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Summary{}, fmt.Errorf("Gemini APIエラー: status=%d", resp.StatusCode)
+	}
+
+	var genResp geminiGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return Summary{}, fmt.Errorf("Geminiレスポンスの解析エラー: %v", err)
+	}
+	if len(genResp.Candidates) == 0 || len(genResp.Candidates[0].Content.Parts) == 0 {
+		return Summary{}, fmt.Errorf("Geminiからの有効なレスポンスがありません")
+	}
+
+	return parseSummaryJSON(genResp.Candidates[0].Content.Parts[0].Text)
+}
@@ -0,0 +1,80 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const openAIChatCompletionsURL = "https://api.openai.com/v1/chat/completions"
+
+type openAISummarizer struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func newOpenAISummarizer(apiKey string) (*openAISummarizer, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEYが設定されていません")
+	}
+	return &openAISummarizer{apiKey: apiKey, model: "gpt-4o-mini", client: http.DefaultClient}, nil
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (s *openAISummarizer) Summarize(ctx context.Context, in Input) (Summary, error) {
+	reqBody := openAIChatRequest{
+		Model: s.model,
+		Messages: []openAIChatMessage{
+			{Role: "user", Content: prompt(in)},
+		},
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIChatCompletionsURL, bytes.NewReader(data))
+	if err != nil {
+		return Summary{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Summary{}, fmt.Errorf("OpenAI APIリクエストエラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Summary{}, fmt.Errorf("OpenAI APIエラー: status=%d", resp.StatusCode)
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return Summary{}, fmt.Errorf("OpenAIレスポンスの解析エラー: %v", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return Summary{}, fmt.Errorf("OpenAIからの有効なレスポンスがありません")
+	}
+
+	return parseSummaryJSON(chatResp.Choices[0].Message.Content)
+}
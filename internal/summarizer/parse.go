@@ -0,0 +1,43 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// summaryJSON はプロンプトで指定したレスポンス形式に対応する中間表現。
+type summaryJSON struct {
+	TLDR      string `json:"tldr"`
+	KeyPoints []string `json:"key_points"`
+	Chapters  []struct {
+		Timestamp string `json:"timestamp"`
+		Title     string `json:"title"`
+	} `json:"chapters"`
+}
+
+// parseSummaryJSON はモデルの生テキスト出力からJSON部分を取り出して Summary に変換する。
+// モデルがコードブロックで囲んで返すことがあるため、先頭と末尾の ```json ... ``` を剥がしてから解析する。
+func parseSummaryJSON(raw string) (Summary, error) {
+	cleaned := strings.TrimSpace(raw)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+	cleaned = strings.TrimSpace(cleaned)
+
+	var parsed summaryJSON
+	if err := json.Unmarshal([]byte(cleaned), &parsed); err != nil {
+		return Summary{}, fmt.Errorf("要約レスポンスのJSON解析エラー: %v (raw=%q)", err, raw)
+	}
+
+	chapters := make([]Chapter, len(parsed.Chapters))
+	for i, c := range parsed.Chapters {
+		chapters[i] = Chapter{Timestamp: c.Timestamp, Title: c.Title}
+	}
+
+	return Summary{
+		TLDR:      parsed.TLDR,
+		KeyPoints: parsed.KeyPoints,
+		Chapters:  chapters,
+	}, nil
+}
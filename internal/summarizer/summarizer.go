@@ -0,0 +1,75 @@
+// Package summarizer は、動画のタイトル・説明・字幕から構造化された要約を
+// 生成するステップを抽象化する。
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Chapter は字幕のキューから読み取ったタイムスタンプ付きの章。
+type Chapter struct {
+	Timestamp string // "mm:ss" 形式
+	Title     string
+}
+
+// Summary は1本の動画に対する構造化要約。
+type Summary struct {
+	TLDR      string // 3文程度の要約
+	KeyPoints []string
+	Chapters  []Chapter
+}
+
+// Input は要約対象の動画情報。
+type Input struct {
+	Title       string
+	Description string
+	CaptionText string // 複数トラックを連結したもの
+}
+
+// Summarizer は Input から構造化された Summary を生成する。
+type Summarizer interface {
+	Summarize(ctx context.Context, in Input) (Summary, error)
+}
+
+// Enabled は、要約機能が有効化されているかどうかを返す。
+// APIキーを持たないユーザーは今まで通り生の字幕をNotionに書き込む挙動のままにするため、
+// 明示的に ENABLE_SUMMARIZATION=1 を設定しない限り無効とする。
+func Enabled() bool {
+	return os.Getenv("ENABLE_SUMMARIZATION") == "1"
+}
+
+// NewFromEnv は SUMMARIZER_PROVIDER 環境変数（openai/gemini/anthropic）に
+// 応じた Summarizer を組み立てる。
+func NewFromEnv() (Summarizer, error) {
+	provider := os.Getenv("SUMMARIZER_PROVIDER")
+	switch provider {
+	case "openai":
+		return newOpenAISummarizer(os.Getenv("OPENAI_API_KEY"))
+	case "gemini":
+		return newGeminiSummarizer(os.Getenv("GEMINI_API_KEY"))
+	case "anthropic":
+		return newAnthropicSummarizer(os.Getenv("ANTHROPIC_API_KEY"))
+	default:
+		return nil, fmt.Errorf("不明なSUMMARIZER_PROVIDERです: %q (openai/gemini/anthropicのいずれかを指定してください)", provider)
+	}
+}
+
+// prompt は各プロバイダ実装が共通で使う要約プロンプトを組み立てる。
+func prompt(in Input) string {
+	return fmt.Sprintf(`以下のYouTube動画の内容を要約してください。
+
+動画タイトル: %s
+動画説明: %s
+
+字幕内容:
+%s
+
+次のJSON形式のみで出力してください（説明文やコードブロックは不要）:
+{
+  "tldr": "3文程度の要約",
+  "key_points": ["要点1", "要点2", "..."],
+  "chapters": [{"timestamp": "mm:ss", "title": "章タイトル"}]
+}`, in.Title, in.Description, in.CaptionText)
+}
@@ -0,0 +1,66 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const deeplTranslateURL = "https://api-free.deepl.com/v2/translate"
+
+// deeplTranslator は DeepL API Free/Pro を使う実装。
+type deeplTranslator struct {
+	apiKey string
+	client *http.Client
+}
+
+func newDeepLTranslator(apiKey string) (*deeplTranslator, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("DEEPL_API_KEYが設定されていません")
+	}
+	return &deeplTranslator{apiKey: apiKey, client: http.DefaultClient}, nil
+}
+
+type deeplResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+}
+
+func (t *deeplTranslator) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+	form := url.Values{}
+	form.Set("text", text)
+	form.Set("target_lang", strings.ToUpper(targetLang))
+	if sourceLang != "" {
+		form.Set("source_lang", strings.ToUpper(sourceLang))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, deeplTranslateURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("DeepL APIリクエストエラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("DeepL APIエラー: status=%d", resp.StatusCode)
+	}
+
+	var deeplResp deeplResponse
+	if err := json.NewDecoder(resp.Body).Decode(&deeplResp); err != nil {
+		return "", fmt.Errorf("DeepLレスポンスの解析エラー: %v", err)
+	}
+	if len(deeplResp.Translations) == 0 {
+		return "", fmt.Errorf("DeepLからの有効なレスポンスがありません")
+	}
+	return deeplResp.Translations[0].Text, nil
+}
@@ -0,0 +1,46 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+
+	translatepb "cloud.google.com/go/translate/apiv3"
+	translatepbpb "cloud.google.com/go/translate/apiv3/translatepb"
+)
+
+// googleTranslator は Google Cloud Translation v3 (Advanced) を使う実装。
+type googleTranslator struct {
+	projectID string
+	client    *translatepb.TranslationClient
+}
+
+func newGoogleTranslator(projectID string) (*googleTranslator, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("GOOGLE_CLOUD_PROJECTが設定されていません")
+	}
+	ctx := context.Background()
+	client, err := translatepb.NewTranslationClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Translation v3クライアントの作成エラー: %v", err)
+	}
+	return &googleTranslator{projectID: projectID, client: client}, nil
+}
+
+func (t *googleTranslator) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+	req := &translatepbpb.TranslateTextRequest{
+		Parent:             fmt.Sprintf("projects/%s/locations/global", t.projectID),
+		Contents:           []string{text},
+		MimeType:           "text/plain",
+		SourceLanguageCode: sourceLang,
+		TargetLanguageCode: targetLang,
+	}
+
+	resp, err := t.client.TranslateText(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("Translation v3リクエストエラー: %v", err)
+	}
+	if len(resp.Translations) == 0 {
+		return "", fmt.Errorf("Translation v3からの有効なレスポンスがありません")
+	}
+	return resp.Translations[0].TranslatedText, nil
+}
@@ -0,0 +1,60 @@
+// Package translate は、字幕の言語がユーザー設定の希望言語に含まれない場合に
+// 翻訳を行うバックエンドを抽象化する。
+package translate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Translator はテキストを targetLang (ISO 639-1) に翻訳する。
+type Translator interface {
+	Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error)
+}
+
+// PreferredLanguages は PREFERRED_LANGUAGES 環境変数（カンマ区切りのISOコード、
+// 例: "ja,en"）をパースする。未設定の場合は ["ja"] を既定値とする。
+func PreferredLanguages() []string {
+	raw := os.Getenv("PREFERRED_LANGUAGES")
+	var langs []string
+	for _, l := range strings.Split(raw, ",") {
+		l = strings.TrimSpace(l)
+		if l != "" {
+			langs = append(langs, l)
+		}
+	}
+	if len(langs) == 0 {
+		return []string{"ja"}
+	}
+	return langs
+}
+
+// IsPreferred は lang が希望言語リストに含まれるかどうかを返す。
+// 言語が判定できなかった場合（空文字列）は翻訳のしようがないので希望言語扱いとする。
+func IsPreferred(lang string, preferred []string) bool {
+	if lang == "" {
+		return true
+	}
+	for _, p := range preferred {
+		if p == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// NewFromEnv は TRANSLATE_PROVIDER 環境変数（google/deepl）に応じた
+// Translator を組み立てる。
+func NewFromEnv() (Translator, error) {
+	provider := os.Getenv("TRANSLATE_PROVIDER")
+	switch provider {
+	case "google":
+		return newGoogleTranslator(os.Getenv("GOOGLE_CLOUD_PROJECT"))
+	case "deepl":
+		return newDeepLTranslator(os.Getenv("DEEPL_API_KEY"))
+	default:
+		return nil, fmt.Errorf("不明なTRANSLATE_PROVIDERです: %q (google/deeplのいずれかを指定してください)", provider)
+	}
+}
@@ -0,0 +1,170 @@
+package ytapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CaptionSource は字幕テキストの取得元を示す。
+type CaptionSource string
+
+const (
+	// CaptionSourceAPI は captions.download による公式取得を表す。
+	// 実際にはチャンネル所有者がアップロードした字幕にしか使えないことが多い。
+	CaptionSourceAPI CaptionSource = "api"
+	// CaptionSourceTimedText は watch ページのページから timedtext
+	// エンドポイントを叩くフォールバック経路を表す。ASR（自動生成）字幕は
+	// ほぼこちらでしか取得できない。
+	CaptionSourceTimedText CaptionSource = "timedtext"
+)
+
+var innertubeAPIKeyPattern = regexp.MustCompile(`"INNERTUBE_API_KEY":"([^"]+)"`)
+
+// Cue は字幕1キュー分のテキストと、それが話され始めた動画中の時刻。
+// timedtext 経由の字幕だけが埋められる。captions.download はプレーンな
+// SRT本文しか返さず、構造化された開始時刻を持たないため、apiCaptionFetcher
+// は常に nil を返す。
+type Cue struct {
+	Start time.Duration
+	Text  string
+}
+
+// CaptionFetcher は動画の字幕本文を取得する手段を抽象化する。
+// captions.download は所有者アップロードの字幕にしか使えないため、
+// API経由で取れなかった場合はタイムドテキストのスクレイピングにフォールバックする。
+type CaptionFetcher interface {
+	// Fetch は字幕の全文と、取得できればキュー単位のタイミング情報を返す。
+	Fetch(ctx context.Context, videoID string, caption CaptionInfo) (text string, cues []Cue, err error)
+}
+
+// apiCaptionFetcher は既存の captions.download エンドポイントを使う実装。
+type apiCaptionFetcher struct {
+	client *Client
+}
+
+func (f *apiCaptionFetcher) Fetch(ctx context.Context, videoID string, caption CaptionInfo) (string, []Cue, error) {
+	var body []byte
+	err := f.client.call(ctx, costCaptionsDownload, func() error {
+		resp, err := f.client.service.Captions.Download(caption.TrackID).Download()
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		body = b
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return string(body), nil, nil
+}
+
+// timedTextCaptionFetcher は watch ページから INNERTUBE_API_KEY を抜き出し、
+// https://www.youtube.com/api/timedtext を叩いて字幕本文を取得する。
+// ASR字幕や、第三者が所有するチャンネルの字幕など、公式APIでは
+// ダウンロードできないトラックをカバーするためのフォールバック。
+type timedTextCaptionFetcher struct {
+	httpClient *http.Client
+}
+
+func newTimedTextCaptionFetcher() *timedTextCaptionFetcher {
+	return &timedTextCaptionFetcher{httpClient: http.DefaultClient}
+}
+
+func (f *timedTextCaptionFetcher) Fetch(ctx context.Context, videoID string, caption CaptionInfo) (string, []Cue, error) {
+	watchURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, watchURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("watchページの取得エラー: %v", err)
+	}
+	defer resp.Body.Close()
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// INNERTUBE_API_KEY自体はtimedtextの認証には使わないが、watchページが
+	// 正しく取得できているかの健全性チェックとして抽出しておく。
+	if m := innertubeAPIKeyPattern.FindSubmatch(page); m == nil {
+		return "", nil, fmt.Errorf("watchページからINNERTUBE_API_KEYを抽出できませんでした")
+	}
+
+	timedTextURL := fmt.Sprintf("https://www.youtube.com/api/timedtext?lang=%s&v=%s",
+		url.QueryEscape(caption.Language), url.QueryEscape(videoID))
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, timedTextURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err = f.httpClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("timedtextの取得エラー: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(body) == 0 {
+		return "", nil, fmt.Errorf("timedtextが空でした (videoID=%s, lang=%s)", videoID, caption.Language)
+	}
+
+	cues := parseTimedTextCues(string(body))
+	lines := make([]string, 0, len(cues))
+	for _, c := range cues {
+		lines = append(lines, c.Text)
+	}
+	return strings.Join(lines, "\n"), cues, nil
+}
+
+// timedTextTagPattern は timedtext のXMLレスポンスから各キューの開始時刻と
+// 本文を抜き出すための簡易パターン。<text start="12.34" dur="...">本文</text>
+// の start 属性と "本文" 部分を取り出す。
+var timedTextTagPattern = regexp.MustCompile(`<text start="([0-9.]+)"[^>]*>([^<]*)</text>`)
+
+// parseTimedTextCues は timedtext のXMLレスポンスを Cue のスライスに変換する。
+// start が解析できないキューは（壊れた行として）読み飛ばす。
+func parseTimedTextCues(xmlBody string) []Cue {
+	matches := timedTextTagPattern.FindAllStringSubmatch(xmlBody, -1)
+	cues := make([]Cue, 0, len(matches))
+	for _, m := range matches {
+		text := strings.TrimSpace(unescapeTimedText(m[2]))
+		if text == "" {
+			continue
+		}
+		startSeconds, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		cues = append(cues, Cue{
+			Start: time.Duration(startSeconds * float64(time.Second)),
+			Text:  text,
+		})
+	}
+	return cues
+}
+
+func unescapeTimedText(s string) string {
+	replacer := strings.NewReplacer(
+		"&amp;", "&",
+		"&lt;", "<",
+		"&gt;", ">",
+		"&quot;", `"`,
+		"&#39;", "'",
+	)
+	return replacer.Replace(s)
+}
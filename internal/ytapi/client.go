@@ -0,0 +1,322 @@
+// Package ytapi は、YouTube Data API の呼び出しを一箇所にまとめ、
+// クォータ計測・レート制限・リトライを共通化する。
+package ytapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abadojack/whatlanggo"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// languageDetectionSampleBytes は言語判定に使う先頭バイト数。
+// 字幕全文を渡す必要はなく、ytsyncのlocalパッケージと同様に冒頭だけで十分。
+const languageDetectionSampleBytes = 1024
+
+// detectLanguage は字幕本文の冒頭から whatlanggo で言語を推定し、ISO 639-1
+// コードを返す。空文字列や判定不能な場合は空文字列を返す。
+func detectLanguage(text string) string {
+	sample := text
+	if len(sample) > languageDetectionSampleBytes {
+		sample = sample[:languageDetectionSampleBytes]
+	}
+	if strings.TrimSpace(sample) == "" {
+		return ""
+	}
+	info := whatlanggo.Detect(sample)
+	if !info.IsReliable() {
+		return ""
+	}
+	return info.Lang.Iso6391()
+}
+
+const (
+	quotaStateFile = "ytapi_quota.json"
+	// YouTube Data APIはプロジェクト全体で同時実行を絞ることが推奨されている
+	// ため、秒間の呼び出し数を控えめに制限する。
+	requestsPerSecond = 2
+	maxRetries        = 5
+)
+
+// VideoInfo は呼び出し元（main パッケージ）に渡す動画メタデータ。
+type VideoInfo struct {
+	VideoID      string
+	Title        string
+	Description  string
+	PublishedAt  time.Time
+	ChannelTitle string
+	URL          string
+}
+
+// CaptionInfo は字幕トラック1件分の情報。
+type CaptionInfo struct {
+	TrackID     string
+	Language    string
+	Text        string
+	IsAutomatic bool
+	Source      CaptionSource
+	// DetectedLanguage は本文の冒頭から whatlanggo で推定した言語のISOコード。
+	// 自動生成字幕では Language (caption.Snippet.Language) がしばしば不正確なため、
+	// 実際の翻訳要否判断にはこちらを使う。
+	DetectedLanguage string
+	// Cues は timedtext 経由で取得した場合のキュー単位のタイミング情報。
+	// captions.download 経由（CaptionSourceAPI）では取得できないため空のまま。
+	Cues []Cue
+}
+
+// Client はクォータ計測・レート制限・バックオフを備えた YouTube API クライアント。
+type Client struct {
+	service *youtube.Service
+	limiter *rate.Limiter
+	quota   *quotaCounter
+
+	apiFetcher       CaptionFetcher
+	timedTextFetcher CaptionFetcher
+}
+
+// NewClient は既存の *youtube.Service をラップする Client を作る。
+func NewClient(service *youtube.Service) (*Client, error) {
+	quota, err := newQuotaCounter(quotaStateFile, DefaultDailyQuota)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		service: service,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), 1),
+		quota:   quota,
+	}
+	c.apiFetcher = &apiCaptionFetcher{client: c}
+	c.timedTextFetcher = newTimedTextCaptionFetcher()
+	return c, nil
+}
+
+// call はクォータを予約し、レート制限を待ち、指数バックオフ付きで fn を実行する
+// 共通ヘルパー。fn は1回分のAPI呼び出しを表す。
+func (c *Client) call(ctx context.Context, cost int, fn func() error) error {
+	if err := c.quota.reserve(cost); err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !isRetryable(err) {
+			return err
+		}
+
+		backoff := time.Duration(1<<uint(attempt)) * time.Second
+		jitter := time.Duration(rand.Int63n(int64(time.Second)))
+		wait := backoff + jitter
+		log.Printf("警告: YouTube APIリクエスト失敗 (試行 %d/%d): %v。%v後にリトライします", attempt+1, maxRetries, err, wait)
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("リトライ上限(%d回)に達しました: %v", maxRetries, lastErr)
+}
+
+// isRetryable は、HTTP 403/429/5xx など再試行の余地があるエラーかどうかを
+// googleapi の構造化エラーから判定する。quotaExceeded のような恒久的な
+// 403エラーはリトライしても無駄なので対象外とする。
+func isRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusTooManyRequests {
+			return true
+		}
+		if apiErr.Code >= 500 {
+			return true
+		}
+		if apiErr.Code == http.StatusForbidden {
+			for _, e := range apiErr.Errors {
+				if e.Reason == "quotaExceeded" || e.Reason == "dailyLimitExceeded" {
+					return false
+				}
+			}
+			// rateLimitExceeded や userRateLimitExceeded は一時的なので再試行する。
+			return true
+		}
+		return false
+	}
+	// googleapi.Error でない場合（ネットワークエラー等）は再試行する。
+	return true
+}
+
+// IsQuotaExceeded は err が恒久的なクォータ超過エラーかどうかを判定する。
+// 構造化された googleapi.Error と、内部の *ErrQuotaExceeded の両方を見る。
+func IsQuotaExceeded(err error) bool {
+	var quotaErr *ErrQuotaExceeded
+	if errors.As(err, &quotaErr) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+		for _, e := range apiErr.Errors {
+			if e.Reason == "quotaExceeded" || e.Reason == "dailyLimitExceeded" {
+				return true
+			}
+		}
+	}
+	// サービスアカウント経由の古いエラー文言にもフォールバックする。
+	return err != nil && strings.Contains(err.Error(), "quotaExceeded")
+}
+
+// UploadsPlaylistID はチャンネルのアップロードプレイリストIDを取得する。
+func (c *Client) UploadsPlaylistID(ctx context.Context, channelID string) (string, error) {
+	var playlistID string
+	err := c.call(ctx, costChannelsList, func() error {
+		resp, err := c.service.Channels.List([]string{"contentDetails"}).Id(channelID).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if len(resp.Items) == 0 {
+			return fmt.Errorf("チャンネルが見つかりません: %s", channelID)
+		}
+		playlistID = resp.Items[0].ContentDetails.RelatedPlaylists.Uploads
+		return nil
+	})
+	return playlistID, err
+}
+
+// VideosInChannel は since より後に公開された動画を新しい順に返す。
+func (c *Client) VideosInChannel(ctx context.Context, channelID string, since time.Time) ([]VideoInfo, error) {
+	playlistID, err := c.UploadsPlaylistID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var videos []VideoInfo
+	nextPageToken := ""
+
+pageLoop:
+	for {
+		var items []*youtube.PlaylistItem
+		var pageToken string
+		err := c.call(ctx, costPlaylistItemsList, func() error {
+			call := c.service.PlaylistItems.List([]string{"snippet"}).
+				PlaylistId(playlistID).
+				MaxResults(50).
+				Context(ctx)
+			if nextPageToken != "" {
+				call = call.PageToken(nextPageToken)
+			}
+			resp, err := call.Do()
+			if err != nil {
+				return err
+			}
+			items = resp.Items
+			pageToken = resp.NextPageToken
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("プレイリストアイテムの取得に失敗: %w", err)
+		}
+
+		for _, item := range items {
+			publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+			if err != nil {
+				log.Printf("警告: 動画 %s の日付解析に失敗: %v", item.Snippet.ResourceId.VideoId, err)
+				continue
+			}
+			if !publishedAt.After(since) {
+				break pageLoop
+			}
+			videos = append(videos, VideoInfo{
+				VideoID:      item.Snippet.ResourceId.VideoId,
+				Title:        item.Snippet.Title,
+				Description:  item.Snippet.Description,
+				PublishedAt:  publishedAt,
+				ChannelTitle: item.Snippet.ChannelTitle,
+				URL:          fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.Snippet.ResourceId.VideoId),
+			})
+		}
+
+		if pageToken == "" {
+			break
+		}
+		nextPageToken = pageToken
+	}
+
+	return videos, nil
+}
+
+// CaptionsForVideo は動画の字幕トラック一覧を取得し、本文をダウンロードする。
+func (c *Client) CaptionsForVideo(ctx context.Context, videoID string) ([]CaptionInfo, error) {
+	var items []*youtube.Caption
+	err := c.call(ctx, costCaptionsList, func() error {
+		resp, err := c.service.Captions.List([]string{"snippet"}, videoID).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		items = resp.Items
+		return nil
+	})
+	if err != nil {
+		if IsQuotaExceeded(err) {
+			return nil, err
+		}
+		log.Printf("警告: 動画 %s の字幕一覧取得をスキップ: %v", videoID, err)
+		return []CaptionInfo{}, nil
+	}
+
+	var captions []CaptionInfo
+	for _, item := range items {
+		caption := CaptionInfo{
+			TrackID:     item.Id,
+			Language:    item.Snippet.Language,
+			IsAutomatic: strings.Contains(item.Snippet.TrackKind, "ASR"),
+		}
+
+		// captions.download はチャンネル所有者がアップロードした字幕にしか
+		// 使えないことが多い。ASR（自動生成）トラックは最初から timedtext の
+		// フォールバックを使う。
+		fetcher := c.apiFetcher
+		source := CaptionSourceAPI
+		if caption.IsAutomatic {
+			fetcher = c.timedTextFetcher
+			source = CaptionSourceTimedText
+		}
+
+		text, cues, err := fetcher.Fetch(ctx, videoID, caption)
+		if err != nil && !caption.IsAutomatic {
+			log.Printf("警告: 字幕 %s のAPIダウンロードに失敗、timedtextにフォールバックします: %v", item.Id, err)
+			fetcher = c.timedTextFetcher
+			source = CaptionSourceTimedText
+			text, cues, err = fetcher.Fetch(ctx, videoID, caption)
+		}
+		if err != nil {
+			log.Printf("警告: 字幕 %s の取得に失敗: %v", item.Id, err)
+			continue
+		}
+
+		caption.Text = text
+		caption.Source = source
+		caption.Cues = cues
+		caption.DetectedLanguage = detectLanguage(text)
+		captions = append(captions, caption)
+	}
+
+	return captions, nil
+}
@@ -0,0 +1,109 @@
+package ytapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// 呼び出し種別ごとの YouTube Data API クォータコスト（ユニット）。
+// https://developers.google.com/youtube/v3/determine_quota_cost
+const (
+	costSearchList          = 100
+	costCaptionsList        = 50
+	costCaptionsDownload    = 200
+	costPlaylistItemsList   = 1
+	costChannelsList        = 1
+)
+
+// DefaultDailyQuota は YouTube Data API の標準の1日あたりの割当ユニット数。
+const DefaultDailyQuota = 10000
+
+// ErrQuotaExceeded は、ローカルで見積もったクォータ消費量が上限に達した
+// ことを示す。呼び出し元はこれを検知してリトライせずに処理を打ち切るべき。
+type ErrQuotaExceeded struct {
+	Used  int
+	Limit int
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("YouTube Data APIの推定クォータ消費量が上限に達しました (used=%d, limit=%d)", e.Used, e.Limit)
+}
+
+// quotaState はクォータカウンタの永続化フォーマット。
+type quotaState struct {
+	Date string `json:"date"` // YYYY-MM-DD (Pacific Time, YouTube APIのリセット基準)
+	Used int    `json:"used"`
+}
+
+// quotaCounter は1日あたりの推定クォータ消費量をディスクに永続化しながら
+// カウントする、プロセス間・再実行間で共有されるカウンタ。
+type quotaCounter struct {
+	mu    sync.Mutex
+	path  string
+	limit int
+	state quotaState
+}
+
+func newQuotaCounter(path string, limit int) (*quotaCounter, error) {
+	qc := &quotaCounter{path: path, limit: limit}
+	if err := qc.load(); err != nil {
+		return nil, err
+	}
+	return qc, nil
+}
+
+func (qc *quotaCounter) today() string {
+	// YouTube のクォータはPacific Timeの深夜にリセットされるが、ローカル運用では
+	// 日付境界のずれは許容し、シンプルにUTCの日付で管理する。
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+func (qc *quotaCounter) load() error {
+	data, err := os.ReadFile(qc.path)
+	if os.IsNotExist(err) {
+		qc.state = quotaState{Date: qc.today()}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("クォータ状態ファイルの読み込みエラー: %v", err)
+	}
+
+	var state quotaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("クォータ状態ファイルの解析エラー: %v", err)
+	}
+	if state.Date != qc.today() {
+		state = quotaState{Date: qc.today()}
+	}
+	qc.state = state
+	return nil
+}
+
+func (qc *quotaCounter) save() error {
+	data, err := json.MarshalIndent(qc.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("クォータ状態のエンコードエラー: %v", err)
+	}
+	return os.WriteFile(qc.path, data, 0644)
+}
+
+// reserve は cost ユニットを消費できるか確認し、可能であれば消費を記録する。
+// 上限に達している場合は *ErrQuotaExceeded を返す。
+func (qc *quotaCounter) reserve(cost int) error {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if qc.state.Date != qc.today() {
+		qc.state = quotaState{Date: qc.today()}
+	}
+
+	if qc.state.Used+cost > qc.limit {
+		return &ErrQuotaExceeded{Used: qc.state.Used, Limit: qc.limit}
+	}
+
+	qc.state.Used += cost
+	return qc.save()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const defaultChannelsConfigFile = "channels.json"
+
+// shortsDurationThreshold は ChannelConfig.SkipShorts が使う閾値。
+// これより短い動画はShortsとして扱われる。
+const shortsDurationThreshold = 3 * time.Minute
+
+// ChannelConfig は channels.json の1エントリ。ハードコードされた
+// channelIDs スライスを置き換え、各クリエイターを個別のNotionデータベースに
+// 振り分け、字幕・要約の設定もそれぞれ持たせられるようにする。
+type ChannelConfig struct {
+	ChannelID             string   `json:"channel_id"`
+	Name                  string   `json:"name"`
+	NotionDatabaseID      string   `json:"notion_database_id"`
+	CaptionLanguages      []string `json:"caption_languages"`
+	SummaryPromptTemplate string   `json:"summary_prompt_template"`
+	MinDuration           string   `json:"min_duration"`
+	SkipShorts            bool     `json:"skip_shorts"`
+	Tags                  []string `json:"tags"`
+}
+
+// minDurationOrZero は MinDuration（例: "5m30s"）を time.ParseDuration で
+// パースする。未設定または不正な場合は0を返す。
+func (c ChannelConfig) minDurationOrZero() time.Duration {
+	if c.MinDuration == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.MinDuration)
+	if err != nil {
+		log.Printf("警告: チャンネル %s のmin_duration %qを解釈できません: %v", c.Name, c.MinDuration, err)
+		return 0
+	}
+	return d
+}
+
+func channelsConfigFile() string {
+	if path := os.Getenv("CHANNELS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return defaultChannelsConfigFile
+}
+
+// loadChannelConfigs は path からチャンネル一覧を読み込む。
+// caption_languages を持たないエントリには、従来のハードコードされた
+// 字幕選択の優先順位に合わせて ["ja", "ja-JP"] を既定値として設定する。
+func loadChannelConfigs(path string) ([]ChannelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("チャンネル設定ファイルの読み込みエラー: %v", err)
+	}
+
+	var configs []ChannelConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("チャンネル設定ファイルの解析エラー: %v", err)
+	}
+	for i, c := range configs {
+		if len(c.CaptionLanguages) == 0 {
+			configs[i].CaptionLanguages = []string{"ja", "ja-JP"}
+		}
+	}
+	return configs, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+var (
+	sinceFlag     = flag.String("since", "", "この時刻より後の動画のみ処理する（例: \"72h\" や \"2024-01-01\"）。未指定時はカーソル/--backfillに従う")
+	maxVideosFlag = flag.Int("max-videos", 0, "チャンネルごとに処理する動画数の上限（0以下は無制限）")
+	backfillFlag  = flag.Bool("backfill", false, "保存済みカーソルを無視し、チャンネルの全履歴（または--sinceの範囲）を取り込む")
+	dryRunFlag    = flag.Bool("dry-run", false, "Notionに書き込まず、作成されるはずの内容をログに出力するだけに留める")
+	onlyFlag      = flag.String("only", "", "channels.jsonのnameが一致するチャンネルだけを処理する（デバッグ用）")
+)
+
+// parseSince は --since に渡せる2つの形式を解釈する:
+// "72h" のような time.ParseDuration 互換の文字列は「今からさかのぼった時刻」、
+// "2024-01-01" のような日付は「その日の0時」として扱う。
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("--sinceの形式を解釈できません（例: \"72h\" または \"2024-01-01\"）: %s", raw)
+}
+
+// resolveSince は、そのチャンネルで「これより後の動画を処理する」境界時刻を
+// 決める。--since が明示されていればそれを最優先し、次に --backfill
+// （保存済みカーソルを無視して全履歴）、どちらもなければ保存済みカーソル
+// （初回実行時は initialBackfillWindow だけ遡る）を使う。
+func resolveSince(cursor ChannelCursor, now time.Time) (time.Time, error) {
+	if *sinceFlag != "" {
+		return parseSince(*sinceFlag, now)
+	}
+	if *backfillFlag {
+		return time.Time{}, nil
+	}
+	if cursor.LastPublishedAt.IsZero() {
+		return now.Add(-initialBackfillWindow), nil
+	}
+	return cursor.LastPublishedAt, nil
+}
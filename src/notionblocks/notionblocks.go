@@ -0,0 +1,215 @@
+// Package notionblocks は、長いテキスト（要約、説明、字幕の書き起こし）を
+// Notion APIの制限に沿ったブロックに変換する。各rich_text要素は2000文字が
+// 上限で、ページ作成時に渡せる子ブロックは最初の100件までであり、それを
+// 超える分は後から Block.AppendChildren で追記する必要がある。
+package notionblocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/kkaiki/youtube_summary_to_notion/src/ytapi"
+)
+
+// MaxParagraphLength は、タイムスタンプリンクのプレフィックスを付けても
+// 1段落に収まるよう、Notionのrich_text 2000文字上限に余裕を持たせた値。
+const MaxParagraphLength = 1900
+
+// segmentWindow は、1つのタイムスタンプへのディープリンクの下にまとめる
+// 書き起こしの幅。これがないと読者は1行だけの段落を100個見ることになる。
+const segmentWindow = 60 * time.Second
+
+// MaxChildrenPerRequest は、Notionが PageCreateRequest や
+// AppendBlockChildrenRequest 1回あたりに受け付ける子ブロック数の上限。
+const MaxChildrenPerRequest = 100
+
+// Heading2Block は、指定したタイトルでレベル2の見出しブロックを作る。
+func Heading2Block(title string) notionapi.Block {
+	return &notionapi.Heading2Block{
+		BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeHeading2},
+		Heading2: notionapi.Heading{
+			RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: title}}},
+		},
+	}
+}
+
+// ParagraphBlocks は text を MaxParagraphLength文字ずつの段落ブロックに
+// 分割する。どちらもキュー単位のタイムスタンプを持たないGeminiの要約や
+// 動画の説明で使う。
+func ParagraphBlocks(text string) []notionapi.Block {
+	var blocks []notionapi.Block
+	for _, chunk := range SplitText(text, MaxParagraphLength) {
+		blocks = append(blocks, paragraphBlock(chunk))
+	}
+	return blocks
+}
+
+func paragraphBlock(text string) notionapi.Block {
+	return &notionapi.ParagraphBlock{
+		BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeParagraph},
+		Paragraph: notionapi.Paragraph{
+			RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: text}}},
+		},
+	}
+}
+
+// SplitText は s を最大 maxRunes 文字のチャンクに分割する。最も近い文末の
+// 句読点か空白で区切ることで、単語の途中で切れないようにする。maxRunes を
+// 超える1つの「単語」でも maxRunes で強制的に分割し、常に前進することを
+// 保証する。
+func SplitText(s string, maxRunes int) []string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return nil
+	}
+
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + maxRunes
+		if end >= len(runes) {
+			chunks = append(chunks, string(runes[start:]))
+			break
+		}
+		end = nearestBreak(runes, start, end)
+		chunks = append(chunks, string(runes[start:end]))
+		start = end
+	}
+	return chunks
+}
+
+// nearestBreak は end から start を超えない範囲で逆方向に走査し、
+// 区切り先とする文末の句読点か空白を探す。
+func nearestBreak(runes []rune, start, end int) int {
+	for i := end; i > start; i-- {
+		switch runes[i-1] {
+		case '\n', ' ', '。', '、', '！', '？', '.', '!', '?':
+			return i
+		}
+	}
+	return end
+}
+
+// TranscriptBlocks は、キューを約60秒単位のセグメントにまとめ、空や重複した
+// キュー（yt-dlpの自動字幕によくある現象）をまとめて除去し、各段落の先頭に
+// "[mm:ss](https://youtu.be/VIDEOID?t=SECS)" 形式のディープリンクを付ける。
+// これにより読者はNotionから動画の該当箇所へ直接ジャンプできる。
+func TranscriptBlocks(videoID string, cues []ytapi.Cue) []notionapi.Block {
+	var blocks []notionapi.Block
+	for _, seg := range groupIntoSegments(dedupeCues(cues)) {
+		link := fmt.Sprintf("[%s](https://youtu.be/%s?t=%d) ", formatTimestamp(seg.start), videoID, int(seg.start.Seconds()))
+		for i, chunk := range SplitText(seg.text, MaxParagraphLength-len(link)) {
+			if i == 0 {
+				blocks = append(blocks, paragraphBlock(link+chunk))
+				continue
+			}
+			blocks = append(blocks, paragraphBlock(chunk))
+		}
+	}
+	return blocks
+}
+
+type segment struct {
+	start time.Duration
+	text  string
+}
+
+func groupIntoSegments(cues []ytapi.Cue) []segment {
+	var segments []segment
+	for _, cue := range cues {
+		if len(segments) == 0 || cue.Start-segments[len(segments)-1].start >= segmentWindow {
+			segments = append(segments, segment{start: cue.Start, text: cue.Text})
+			continue
+		}
+		last := &segments[len(segments)-1]
+		last.text += " " + cue.Text
+	}
+	return segments
+}
+
+// dedupeCues は、空のキューと直前のキューと同じテキストのキューを除去する。
+// yt-dlpの自動生成字幕は重なり合うウィンドウの間でこれを頻繁に繰り返す。
+func dedupeCues(cues []ytapi.Cue) []ytapi.Cue {
+	var out []ytapi.Cue
+	var last string
+	for _, c := range cues {
+		text := strings.TrimSpace(c.Text)
+		if text == "" || text == last {
+			continue
+		}
+		out = append(out, ytapi.Cue{Start: c.Start, Text: text})
+		last = text
+	}
+	return out
+}
+
+func formatTimestamp(d time.Duration) string {
+	total := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
+
+// PageCreator は、CreatePageWithBlocks が必要とする notionapi.Client の
+// Pageサービスの部分集合。テストではフェイク実装で満たせる。
+type PageCreator interface {
+	Create(ctx context.Context, request *notionapi.PageCreateRequest) (*notionapi.Page, error)
+}
+
+// BlockAppender は、CreatePageWithBlocks が必要とする notionapi.Client の
+// Blockサービスの部分集合。テストではフェイク実装で満たせる。
+type BlockAppender interface {
+	AppendChildren(ctx context.Context, blockID notionapi.BlockID, request *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error)
+}
+
+// CreateProgress は、リトライをまたいで CreatePageWithBlocks がどこまで
+// 進んだかを保持する。失敗した呼び出しをリトライする側は同じ *CreateProgress
+// を使い回す必要があり、これにより既に作成済みのページを再作成したり、
+// 既に適用済みの追記を繰り返したりしないようにする。ゼロ値は「まだ何も
+// 作成されていない」ことを表す。
+type CreateProgress struct {
+	pageID   notionapi.ObjectID
+	appended int // how many of rest have already been appended
+}
+
+// CreatePageWithBlocks は、allBlocks のうち最大 MaxChildrenPerRequest件を
+// 初期の子ブロックとしてページを作成し、残りは AppendChildren で
+// MaxChildrenPerRequest件ずつ追記する。PageCreateRequest自体は1回の呼び出しで
+// それ以上の子ブロックを渡せないため。progress は同一の論理的なページ書き込みの
+// リトライ間で使い回す必要がある。Create が一度成功すれば、以降の呼び出しでは
+// CreatePageWithBlocks はそれをスキップし、最後に成功したバッチから追記を
+// 再開する。リトライのたびにページを再作成して孤立したページを残すことを
+// 避けるためである。
+func CreatePageWithBlocks(ctx context.Context, pages PageCreator, blocks BlockAppender, params *notionapi.PageCreateRequest, allBlocks []notionapi.Block, progress *CreateProgress) error {
+	firstBatch, rest := allBlocks, []notionapi.Block(nil)
+	if len(allBlocks) > MaxChildrenPerRequest {
+		firstBatch = allBlocks[:MaxChildrenPerRequest]
+		rest = allBlocks[MaxChildrenPerRequest:]
+	}
+
+	if progress.pageID == "" {
+		params.Children = firstBatch
+		page, err := pages.Create(ctx, params)
+		if err != nil {
+			return err
+		}
+		progress.pageID = page.ID
+	}
+
+	rest = rest[progress.appended:]
+	for len(rest) > 0 {
+		batch := rest
+		if len(batch) > MaxChildrenPerRequest {
+			batch = batch[:MaxChildrenPerRequest]
+		}
+		if _, err := blocks.AppendChildren(ctx, notionapi.BlockID(progress.pageID), &notionapi.AppendBlockChildrenRequest{
+			Children: batch,
+		}); err != nil {
+			return fmt.Errorf("ブロックの追記に失敗 (残り%d件): %w", len(rest), err)
+		}
+		progress.appended += len(batch)
+		rest = rest[len(batch):]
+	}
+
+	return nil
+}
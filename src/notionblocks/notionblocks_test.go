@@ -0,0 +1,56 @@
+package notionblocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kkaiki/youtube_summary_to_notion/src/ytapi"
+)
+
+func TestDedupeCues_DropsEmptyAndRepeatedText(t *testing.T) {
+	cues := []ytapi.Cue{
+		{Start: 0, Text: "こんにちは"},
+		{Start: 1 * time.Second, Text: "こんにちは"},
+		{Start: 2 * time.Second, Text: "  "},
+		{Start: 3 * time.Second, Text: "今日は天気がいいですね"},
+	}
+
+	got := dedupeCues(cues)
+	want := []ytapi.Cue{
+		{Start: 0, Text: "こんにちは"},
+		{Start: 3 * time.Second, Text: "今日は天気がいいですね"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("dedupeCues() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("dedupeCues()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGroupIntoSegments_SplitsAtSegmentWindow(t *testing.T) {
+	cues := []ytapi.Cue{
+		{Start: 0, Text: "あ"},
+		{Start: 30 * time.Second, Text: "い"},
+		{Start: segmentWindow, Text: "う"},
+	}
+
+	segments := groupIntoSegments(cues)
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d: %v", len(segments), segments)
+	}
+	if segments[0].start != 0 || segments[0].text != "あ い" {
+		t.Fatalf("unexpected first segment: %+v", segments[0])
+	}
+	if segments[1].start != segmentWindow || segments[1].text != "う" {
+		t.Fatalf("unexpected second segment: %+v", segments[1])
+	}
+}
+
+func TestGroupIntoSegments_Empty(t *testing.T) {
+	if segments := groupIntoSegments(nil); segments != nil {
+		t.Fatalf("expected nil for no cues, got %v", segments)
+	}
+}
@@ -0,0 +1,47 @@
+package summarizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+const defaultCacheDir = "summarizer_cache"
+
+// diskCache は部分要約を元チャンクのハッシュ値をキーとして永続化する。
+// 同じ動画でパイプラインを再実行してもGeminiを呼び直さずに済む。
+type diskCache struct {
+	dir string
+}
+
+func newDiskCache(dir string) *diskCache {
+	return &diskCache{dir: dir}
+}
+
+func cacheDirFromEnv() string {
+	if dir := os.Getenv("SUMMARIZER_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return defaultCacheDir
+}
+
+func chunkCacheKey(chunk string) string {
+	sum := sha256.Sum256([]byte(chunk))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *diskCache) get(key string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+func (c *diskCache) put(key, value string) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(c.dir, key), []byte(value), 0o644)
+}
@@ -0,0 +1,46 @@
+package summarizer
+
+// splitIntoWindows は text をおよそ windowSize 文字ずつ重なりを持たせて分割する。
+// overlap 文字分の文脈を次のウィンドウにも繰り返し含め、最も近い改行または
+// 文末の句読点で区切ることで、文の途中でウィンドウが切れないようにする。
+func splitIntoWindows(text string, windowSize, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) <= windowSize {
+		return []string{text}
+	}
+
+	var windows []string
+	start := 0
+	for start < len(runes) {
+		end := start + windowSize
+		if end >= len(runes) {
+			windows = append(windows, string(runes[start:]))
+			break
+		}
+		end = nearestBoundary(runes, end)
+		windows = append(windows, string(runes[start:end]))
+
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return windows
+}
+
+// nearestBoundary は pos から先へ最大200文字分、改行または文末の句読点を
+// 探す。見つからなければ pos をそのまま返す。
+func nearestBoundary(runes []rune, pos int) int {
+	limit := pos + 200
+	if limit > len(runes) {
+		limit = len(runes)
+	}
+	for i := pos; i < limit; i++ {
+		switch runes[i] {
+		case '\n', '。', '！', '？', '.', '!', '?':
+			return i + 1
+		}
+	}
+	return pos
+}
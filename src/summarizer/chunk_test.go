@@ -0,0 +1,60 @@
+package summarizer
+
+import "testing"
+
+func TestSplitIntoWindows_ShortTextReturnsSingleWindow(t *testing.T) {
+	text := "短い書き起こしです。"
+	windows := splitIntoWindows(text, 100, 10)
+
+	if len(windows) != 1 || windows[0] != text {
+		t.Fatalf("splitIntoWindows() = %v, want [%q]", windows, text)
+	}
+}
+
+func TestSplitIntoWindows_BreaksOnSentenceBoundary(t *testing.T) {
+	text := "あ。い。う。え。お。"
+	windows := splitIntoWindows(text, 5, 0)
+
+	if len(windows) < 2 {
+		t.Fatalf("expected at least 2 windows, got %d: %v", len(windows), windows)
+	}
+	for _, w := range windows[:len(windows)-1] {
+		runes := []rune(w)
+		last := runes[len(runes)-1]
+		if last != '。' {
+			t.Fatalf("expected window %q to end on a sentence boundary, ended on %q", w, string(last))
+		}
+	}
+}
+
+func TestSplitIntoWindows_OverlapCarriesContextIntoNextWindow(t *testing.T) {
+	runes := make([]rune, 30)
+	for i := range runes {
+		runes[i] = 'a' + rune(i%26)
+	}
+	text := string(runes)
+
+	windows := splitIntoWindows(text, 20, 5)
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d: %v", len(windows), windows)
+	}
+	tail := []rune(windows[0])
+	tail = tail[len(tail)-5:]
+	if string(tail) != windows[1][:len(string(tail))] {
+		t.Fatalf("expected the last 5 runes of window 0 (%q) to reappear at the start of window 1 (%q)", string(tail), windows[1])
+	}
+}
+
+func TestNearestBoundary_FindsPunctuationWithinLimit(t *testing.T) {
+	runes := []rune("abc。def")
+	if got := nearestBoundary(runes, 2); got != 4 {
+		t.Fatalf("nearestBoundary() = %d, want 4", got)
+	}
+}
+
+func TestNearestBoundary_FallsBackToPosWhenNoneFound(t *testing.T) {
+	runes := []rune("abcdefghij")
+	if got := nearestBoundary(runes, 3); got != 3 {
+		t.Fatalf("nearestBoundary() = %d, want 3", got)
+	}
+}
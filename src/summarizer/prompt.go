@@ -0,0 +1,50 @@
+package summarizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partialSummaryPrompt は、書き起こしの1ウィンドウ分だけを対象に、動画の
+// 他の部分を気にせず単独で要約するようGeminiに依頼するプロンプトを作る。
+func partialSummaryPrompt(chunk string) string {
+	return fmt.Sprintf(`以下は長い動画の書き起こしの一部です。この部分のみを対象に、
+主要なポイントを3〜5個の箇条書きで挙げ、印象的な発言や引用があれば合わせて挙げてください。
+他の部分の内容には言及しないでください。
+
+書き起こし:
+%s`, chunk)
+}
+
+// defaultInstructions は、チャンネルが summary_prompt_template で上書きしない
+// 場合に使われる「この形式で要約してください」という末尾の指示文。
+const defaultInstructions = `以下の形式で要約してください：
+1. 主要ポイント（3-5個）
+2. 重要な発言
+3. 結論
+
+要約は500文字以内で簡潔にまとめてください。`
+
+// reducePrompt は各ウィンドウの部分要約を統合し、最終レポートにする。
+// instructions が空でなければ defaultInstructions の代わりに使われ、
+// チャンネルの summary_prompt_template で希望の形式に変更できる。
+func reducePrompt(title, description string, partials []string, instructions string) string {
+	body := "書き起こしは利用できません。"
+	if len(partials) > 0 {
+		body = strings.Join(partials, "\n\n---\n\n")
+	}
+	if instructions == "" {
+		instructions = defaultInstructions
+	}
+
+	return fmt.Sprintf(`以下はYouTube動画を区間ごとに分割して作成した部分要約です。
+これらを統合し、動画全体の要約を日本語で作成してください。
+
+動画タイトル: %s
+動画説明: %s
+
+区間ごとの部分要約:
+%s
+
+%s`, title, description, body, instructions)
+}
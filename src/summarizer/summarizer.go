@@ -0,0 +1,178 @@
+// Package summarizer は、map-reduce方式で動画の書き起こしから長さの
+// 上限を定めた日本語要約を作る。書き起こしを重なりのあるウィンドウに
+// 分割し、各ウィンドウを並列で独立に要約し（map）、部分要約を最終的な
+// 3セクションのレポートに統合する（reduce）。これは固定文字数での
+// 書き起こし切り詰めを置き換えるもので、従来の方式では1時間を超える
+// 動画の大半が黙って失われていた。
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/generative-ai-go/genai"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+)
+
+const (
+	defaultWindowSize    = 6000
+	defaultOverlap       = 500
+	defaultConcurrency   = 3
+	defaultRPM           = 60
+	maxChunkGrowAttempts = 2
+)
+
+// Summarizer は Gemini API に対してmap-reduce方式の要約パイプラインを実行する。
+type Summarizer struct {
+	apiKey      string
+	limiter     *rate.Limiter
+	concurrency int
+	cache       *diskCache
+}
+
+// New は、1分あたり rpm リクエストに制限し、同時実行数を concurrency 件までに
+// 抑えてGeminiを呼び出す Summarizer を組み立てる。rpm <= 0 または
+// concurrency <= 0 の場合は妥当な既定値にフォールバックする。
+func New(apiKey string, rpm, concurrency int) *Summarizer {
+	if rpm <= 0 {
+		rpm = defaultRPM
+	}
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	return &Summarizer{
+		apiKey:      apiKey,
+		limiter:     rate.NewLimiter(rate.Limit(float64(rpm)/60), 1),
+		concurrency: concurrency,
+		cache:       newDiskCache(cacheDirFromEnv()),
+	}
+}
+
+// NewFromEnv は、SUMMARIZER_RPM / SUMMARIZER_CONCURRENCY が設定されていれば
+// それを使い、なければ New と同じ既定値で Summarizer を組み立てる。
+func NewFromEnv(apiKey string) *Summarizer {
+	return New(apiKey, envInt("SUMMARIZER_RPM", 0), envInt("SUMMARIZER_CONCURRENCY", 0))
+}
+
+func envInt(name string, fallback int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// Summarize は、動画のタイトル・説明・連結した字幕テキストから最終的な
+// 日本語要約を作る。instructions は reduce プロンプト末尾の「この形式で
+// 要約してください」を上書きする（チャンネルごとの summary_prompt_template
+// など）。既定値を使う場合は "" を渡す。
+func (s *Summarizer) Summarize(ctx context.Context, title, description, captionText, instructions string) (string, error) {
+	if strings.TrimSpace(captionText) == "" {
+		return s.reduce(ctx, title, description, nil, instructions)
+	}
+
+	windowSize := defaultWindowSize
+	var partials []string
+	var err error
+	for attempt := 0; attempt <= maxChunkGrowAttempts; attempt++ {
+		chunks := splitIntoWindows(captionText, windowSize, defaultOverlap)
+		partials, err = s.mapPartials(ctx, chunks)
+		if err == nil {
+			break
+		}
+		log.Printf("警告: チャンクサイズ%d文字での部分要約に失敗（セーフティブロックまたは空レスポンスの可能性）、チャンクを拡大して再試行します: %v", windowSize, err)
+		windowSize *= 2
+	}
+	if err != nil {
+		return "", fmt.Errorf("部分要約の生成に失敗: %v", err)
+	}
+
+	return s.reduce(ctx, title, description, partials, instructions)
+}
+
+// mapPartials は各チャンクを独立に要約する。同時実行数は s.concurrency、
+// リクエスト数は s.limiter で制限し、いずれかのチャンクの要約が失敗したら
+// エラーを返す。
+func (s *Summarizer) mapPartials(ctx context.Context, chunks []string) ([]string, error) {
+	partials := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.concurrency)
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			partials[i], errs[i] = s.partialSummary(ctx, chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return partials, nil
+}
+
+func (s *Summarizer) partialSummary(ctx context.Context, chunk string) (string, error) {
+	key := chunkCacheKey(chunk)
+	if cached, ok := s.cache.get(key); ok {
+		return cached, nil
+	}
+
+	text, err := s.generate(ctx, partialSummaryPrompt(chunk))
+	if err != nil {
+		return "", err
+	}
+
+	s.cache.put(key, text)
+	return text, nil
+}
+
+func (s *Summarizer) reduce(ctx context.Context, title, description string, partials []string, instructions string) (string, error) {
+	return s.generate(ctx, reducePrompt(title, description, partials, instructions))
+}
+
+func (s *Summarizer) generate(ctx context.Context, prompt string) (string, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	client, err := genai.NewClient(ctx, option.WithAPIKey(s.apiKey))
+	if err != nil {
+		return "", fmt.Errorf("Geminiクライアントの作成エラー: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.GenerativeModel("gemini-pro").GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", fmt.Errorf("Gemini APIリクエストエラー: %v", err)
+	}
+	return candidateText(resp)
+}
+
+func candidateText(resp *genai.GenerateContentResponse) (string, error) {
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("Gemini APIからの有効なレスポンスがありません")
+	}
+	var text string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text += fmt.Sprint(part)
+	}
+	return text, nil
+}
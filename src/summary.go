@@ -1,23 +1,24 @@
 package main
 
 import (
-	"io"
     "context"
+    "flag"
     "fmt"
     "log"
     "os"
+    "sort"
     "time"
-    "strings"
     "sync"
     "encoding/json"
     "net/http"
     "github.com/jomei/notionapi"
+    "google.golang.org/api/option"
     "google.golang.org/api/youtube/v3"
     "golang.org/x/oauth2"
     "golang.org/x/oauth2/google"
-    "github.com/google/generative-ai-go/genai"
-    "encoding/xml"
-    "os/exec"
+    "github.com/kkaiki/youtube_summary_to_notion/src/ytapi"
+    "github.com/kkaiki/youtube_summary_to_notion/src/summarizer"
+    "github.com/kkaiki/youtube_summary_to_notion/src/notionblocks"
 )
 
 const (
@@ -41,6 +42,7 @@ type CaptionInfo struct {
     Language    string
     Text        string
     IsAutomatic bool
+    Cues        []ytapi.Cue // VTT/SRT由来の場合のみ埋まる。notionblocksのタイムスタンプ付きリンク生成に使う
 }
 
 // Gemini API用の構造体
@@ -64,15 +66,6 @@ type GeminiCandidate struct {
     Content GeminiContent `json:"content"`
 }
 
-// RSSフィード用の構造体
-// <feed><entry><yt:videoId>...</yt:videoId></yt:videoId></entry></feed>
-type Feed struct {
-    Entries []Entry `xml:"entry"`
-}
-type Entry struct {
-    VideoID string `xml:"videoId"`
-}
-
 // 説明文を制限する関数
 func truncateDescription(description string) string {
     runes := []rune(description)
@@ -82,58 +75,86 @@ func truncateDescription(description string) string {
     return description
 }
 
-// RSSフィードから最新動画IDを取得
-func getLatestVideoIDFromRSS(channelID string) (string, error) {
-    url := fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", channelID)
-    resp, err := http.Get(url)
-    if err != nil {
-        return "", err
+// newYtapiClient は利用可能なトランスポート（OAuth優先、なければAPIキー）を
+// 登録した ytapi.Client を組み立てる。どちらも設定されていなくても、
+// スクレイパー（RSS + yt-dlp）だけで動作できる。
+func newYtapiClient(ctx context.Context) (*ytapi.Client, error) {
+    var oauthService, apiKeyService *youtube.Service
+
+    if client, err := getServiceAccountClient(); err == nil {
+        oauthService, err = youtube.NewService(ctx, option.WithHTTPClient(client))
+        if err != nil {
+            log.Printf("警告: OAuthサービスの初期化に失敗: %v", err)
+            oauthService = nil
+        }
+    } else {
+        log.Printf("警告: サービスアカウントクライアントを利用できません: %v", err)
     }
-    defer resp.Body.Close()
-    body, err := io.ReadAll(resp.Body)
-    if err != nil {
-        return "", err
+
+    if apiKey := os.Getenv("YOUTUBE_API_KEY"); apiKey != "" {
+        var err error
+        apiKeyService, err = youtube.NewService(ctx, option.WithAPIKey(apiKey))
+        if err != nil {
+            log.Printf("警告: APIキーサービスの初期化に失敗: %v", err)
+            apiKeyService = nil
+        }
     }
-    var feed Feed
-    if err := xml.Unmarshal(body, &feed); err != nil {
-        return "", err
+
+    return ytapi.NewClient(oauthService, apiKeyService, ytapi.NewIPPoolFromEnv()), nil
+}
+
+func main() {
+    flag.Parse()
+
+    ctx := context.Background()
+    ytClient, err := newYtapiClient(ctx)
+    if err != nil {
+        log.Fatalf("ytapiクライアントの作成に失敗: %v", err)
     }
-    if len(feed.Entries) == 0 {
-        return "", fmt.Errorf("no videos found")
+
+    notionClient := notionapi.NewClient(notionapi.Token(os.Getenv("NOTION_API_KEY")))
+    defaultDatabaseID := os.Getenv("NOTION_DATABASE_ID")
+    geminiAPIKey := os.Getenv("GEMINI_API_KEY")
+
+    state, err := loadSyncState(syncStateFile)
+    if err != nil {
+        log.Fatalf("同期状態の読み込みに失敗: %v", err)
     }
-    return feed.Entries[0].VideoID, nil
-}
 
-// yt-dlpで日本語字幕をダウンロード
-func downloadJapaneseSubtitle(videoID string) error {
-    url := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-    cmd := exec.Command("yt-dlp", "--write-auto-sub", "--sub-lang", "ja", "--skip-download", url)
-    out, err := cmd.CombinedOutput()
+    configs, err := loadChannelConfigs(channelsConfigFile())
     if err != nil {
-        return fmt.Errorf("yt-dlp error: %v, output: %s", err, string(out))
+        log.Fatalf("チャンネル設定の読み込みに失敗: %v", err)
     }
-    return nil
-}
 
-func main() {
-    // チャンネルIDのリスト
-    channelIDs := []string{
-        "UCagAVZFPcLh9UMDidIUfXKQ", // MBチャンネル
-        "UC67Wr_9pA4I0glIxDt_Cpyw", // 学長
-        "UCXjTiSGclQLVVU83GVrRM4w", // ホリエモン
-    }
-    for _, channelID := range channelIDs {
-        videoID, err := getLatestVideoIDFromRSS(channelID)
-        if err != nil {
-            log.Printf("チャンネル%sの最新動画ID取得失敗: %v", channelID, err)
+    now := time.Now()
+    for _, cfg := range configs {
+        if *onlyFlag != "" && cfg.Name != *onlyFlag {
             continue
         }
-        log.Printf("チャンネル%sの最新動画ID: %s", channelID, videoID)
-        if err := downloadJapaneseSubtitle(videoID); err != nil {
-            log.Printf("字幕ダウンロード失敗: %v", err)
+        if cfg.NotionDatabaseID == "" {
+            cfg.NotionDatabaseID = defaultDatabaseID
+        }
+
+        since, err := resolveSince(state.Channels[cfg.ChannelID], now)
+        if err != nil {
+            log.Fatalf("--sinceの解釈に失敗: %v", err)
+        }
+
+        cursor, err := processChannel(ctx, ytClient, notionClient, cfg, geminiAPIKey, since, *maxVideosFlag)
+        if err != nil {
+            log.Printf("エラー: チャンネル %s の処理に失敗: %v", cfg.ChannelID, err)
             continue
         }
-        log.Printf("字幕ダウンロード完了: %s", videoID)
+        if cursor != nil {
+            state.Channels[cfg.ChannelID] = *cursor
+            // チャンネルごとに即座に保存する。ループ全体が終わるまで待つと、
+            // 途中で落ちた(cronのタイムアウト、OOM等)場合にそれまで成功した
+            // チャンネルのカーソルも失われ、ダウンタイム後の再開・1日複数回の
+            // 再実行という本来の目的を満たせない。
+            if err := saveSyncState(syncStateFile, state); err != nil {
+                log.Printf("エラー: 同期状態の保存に失敗: %v", err)
+            }
+        }
     }
 }
 
@@ -231,46 +252,100 @@ func getOAuthClient() (*http.Client, error) {
     return getClient(config), nil
 }
 // processChannel 関数の修正
-func processChannel(ctx context.Context, youtubeService *youtube.Service, notionClient *notionapi.Client, channelID, databaseID, geminiAPIKey string) {
-    
-    videos, err := getLatestVideos(youtubeService, channelID)
+// ytClient は OAuth/APIキー/スクレイパーのどれが実際に応答したかを意識させず、
+// 「動画+字幕を取る」という1つの呼び出しとして扱える。
+// since より後に公開された動画だけを対象にし（「今日公開された動画」という
+// 日付ベースのフィルタだと、cronの実行漏れで動画を永久に取りこぼしてしまう）、
+// maxVideos > 0 ならチャンネルごとに処理する動画数を古い順に制限する。
+// cfg.SkipShorts / cfg.MinDuration はData API経由で動画時間が取れた場合のみ
+// 有効になる（スクレイパー経由のフォールバックでは動画時間は常にゼロ値）。
+// 戻り値は今回処理した中で最も新しい動画のカーソルで、次回実行の起点になる。
+// 動画が1件もなければ nil を返し、呼び出し元は既存のカーソルを保持する。
+func processChannel(ctx context.Context, ytClient *ytapi.Client, notionClient *notionapi.Client, cfg ChannelConfig, geminiAPIKey string, since time.Time, maxVideos int) (*ChannelCursor, error) {
+    apiVideos, err := ytClient.VideosInChannel(ctx, cfg.ChannelID, since)
     if err != nil {
-        log.Printf("エラー: チャンネル %s の動画取得に失敗: %v", channelID, err)
-        return
+        return nil, fmt.Errorf("チャンネル %s の動画取得に失敗: %v", cfg.ChannelID, err)
+    }
+
+    minDuration := cfg.minDurationOrZero()
+    videos := make([]VideoInfo, 0, len(apiVideos))
+    for _, v := range apiVideos {
+        if cfg.SkipShorts && v.Duration > 0 && v.Duration < shortsDurationThreshold {
+            continue
+        }
+        if minDuration > 0 && v.Duration > 0 && v.Duration < minDuration {
+            continue
+        }
+        videos = append(videos, VideoInfo{
+            VideoID:      v.VideoID,
+            Title:        v.Title,
+            Description:  truncateDescription(v.Description),
+            PublishedAt:  v.PublishedAt,
+            ChannelTitle: v.ChannelTitle,
+            URL:          v.URL,
+        })
+    }
+
+    // ytapi.VideosInChannel は新しい順に返すため、古い順に並び替えてから処理する。
+    sort.Slice(videos, func(i, j int) bool {
+        return videos[i].PublishedAt.Before(videos[j].PublishedAt)
+    })
+    if maxVideos > 0 && len(videos) > maxVideos {
+        videos = videos[:maxVideos]
     }
-    log.Printf("チャンネル %s から %d 件の動画を取得しました", channelID, len(videos))
+    log.Printf("チャンネル %s から %d 件の動画を取得しました (since=%s)", cfg.ChannelID, len(videos), since.Format(time.RFC3339))
 
     var wg sync.WaitGroup
+    var mu sync.Mutex
     semaphore := make(chan struct{}, 3)
 
-    for _, video := range videos {
+    // 各動画の処理結果（成功したか）を記録し、wg.Wait() の後にまとめて
+    // カーソルを決定する。videos は既に古い順に並んでいるため、成功が
+    // 途切れた時点より前までしかカーソルを進めてはならない。
+    successes := make([]bool, len(videos))
+    recordResult := func(i int, success bool) {
+        mu.Lock()
+        successes[i] = success
+        mu.Unlock()
+    }
+
+    for i, video := range videos {
         wg.Add(1)
-        go func(v VideoInfo) {
+        go func(i int, v VideoInfo) {
             defer wg.Done()
             semaphore <- struct{}{}
             defer func() { <-semaphore }()
 
 
-            exists, err := checkDuplicateInNotion(notionClient, databaseID, v.VideoID)
+            exists, err := checkDuplicateInNotion(notionClient, cfg.NotionDatabaseID, v.VideoID)
             if err != nil {
                 log.Printf("エラー: 重複チェック中 (VideoID: %s): %v", v.VideoID, err)
                 return
             }
             if exists {
                 log.Printf("スキップ: 動画 %s は既にNotionに存在します", v.VideoID)
+                recordResult(i, true)
                 return
             }
 
-            captions, err := getCaptions(youtubeService, v.VideoID)
+            apiCaptions, err := ytClient.CaptionsForVideo(ctx, v.VideoID)
             if err != nil {
                 log.Printf("警告: 動画 %s の字幕取得に失敗: %v", v.VideoID, err)
             } else {
-                log.Printf("字幕取得完了: %s (%d 件の字幕)", v.VideoID, len(captions))
+                log.Printf("字幕取得完了: %s (%d 件の字幕)", v.VideoID, len(apiCaptions))
+            }
+            for _, c := range apiCaptions {
+                v.Captions = append(v.Captions, CaptionInfo{
+                    Language:    c.Language,
+                    Text:        c.Text,
+                    IsAutomatic: c.IsAutomatic,
+                    Cues:        c.Cues,
+                })
             }
-            v.Captions = captions
 
             // Gemini APIを使用して要約を生成
-            summary, err := summarizeWithGemini(geminiAPIKey, v)
+            captionText := selectCaptionText(v.Captions, cfg.CaptionLanguages)
+            summary, err := summarizeWithGemini(geminiAPIKey, v, captionText, cfg.SummaryPromptTemplate)
             if err != nil {
                 log.Printf("警告: 動画 %s の要約生成に失敗: %v", v.VideoID, err)
                 v.Summary = "要約の生成に失敗しました。"
@@ -278,111 +353,41 @@ func processChannel(ctx context.Context, youtubeService *youtube.Service, notion
                 v.Summary = summary
             }
 
-            err = saveToNotionWithRetry(notionClient, databaseID, v, 3)
+            if *dryRunFlag {
+                log.Printf("[dry-run] Notionへの書き込みをスキップ: %s (%s, tags=%v)", v.Title, v.VideoID, cfg.Tags)
+                return
+            }
+
+            err = saveToNotionWithRetry(notionClient, cfg.NotionDatabaseID, v, cfg.Tags, 3)
             if err != nil {
                 log.Printf("エラー: Notionへの保存失敗 (VideoID: %s): %v", v.VideoID, err)
                 return
             }
-        }(video)
+            recordResult(i, true)
+        }(i, video)
     }
 
     wg.Wait()
-}
-
-// getLatestVideos 関数の修正
-func getLatestVideos(service *youtube.Service, channelID string) ([]VideoInfo, error) {
-    channelResponse, err := service.Channels.List([]string{"contentDetails"}).
-        Id(channelID).
-        Do()
-    if err != nil {
-        log.Printf("チャンネル情報取得エラー: %v", err)
-        return nil, err
-    }
-    log.Printf("チャンネル情報取得成功")
-
-    if len(channelResponse.Items) == 0 {
-        return nil, fmt.Errorf("チャンネルが見つかりません")
-    }
 
-    uploadsPlaylistID := channelResponse.Items[0].ContentDetails.RelatedPlaylists.Uploads
-    log.Printf("アップロードプレイリストID: %s", uploadsPlaylistID)
+    return cursorFromSuccesses(videos, successes), nil
+}
 
-    var videos []VideoInfo
-    nextPageToken := ""
-    now := time.Now().In(time.Local)
-    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
-    filteredCount := 0
-    for {
-        playlistCall := service.PlaylistItems.List([]string{"snippet"}).
-            PlaylistId(uploadsPlaylistID).
-            MaxResults(50)
-        if nextPageToken != "" {
-            playlistCall = playlistCall.PageToken(nextPageToken)
-        }
-        playlistResponse, err := playlistCall.Do()
-        if err != nil {
-            return nil, fmt.Errorf("プレイリストアイテムの取得に失敗: %v", err)
-        }
-        for _, item := range playlistResponse.Items {
-            publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
-            if err != nil {
-                log.Printf("警告: 動画 %s の日付解析に失敗: %v", item.Snippet.ResourceId.VideoId, err)
-                continue
-            }
-            publishedAtJST := publishedAt.In(time.Local)
-            publishedDate := time.Date(publishedAtJST.Year(), publishedAtJST.Month(), publishedAtJST.Day(), 0, 0, 0, 0, time.Local)
-            if publishedDate.Equal(today) {
-                video := VideoInfo{
-                    VideoID:      item.Snippet.ResourceId.VideoId,
-                    Title:        item.Snippet.Title,
-                    Description:  truncateDescription(item.Snippet.Description),
-                    PublishedAt:  publishedAt,
-                    ChannelTitle: item.Snippet.ChannelTitle,
-                    URL:         fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.Snippet.ResourceId.VideoId),
-                }
-                videos = append(videos, video)
-                filteredCount++
-                log.Printf("今日の動画を追加: %s (%s)", video.Title, video.PublishedAt.Format("2006-01-02 15:04:05"))
-            }
-        }
-        if playlistResponse.NextPageToken == "" {
+// cursorFromSuccesses は、古い順に並んだ videos を見て、最初に失敗した
+// （または dry-run でスキップした）動画より前までしかカーソルを進めない。
+// そこを飛び越えて進めると、次回実行時に since より古いという理由でその
+// 動画が二度と再試行されなくなる。成功した動画が1件もなければ nil を返す。
+func cursorFromSuccesses(videos []VideoInfo, successes []bool) *ChannelCursor {
+    var newest *VideoInfo
+    for i := range videos {
+        if !successes[i] {
             break
         }
-        nextPageToken = playlistResponse.NextPageToken
+        newest = &videos[i]
     }
-    log.Printf("チャンネル %s から今日の動画 %d 件を抽出しました", channelID, filteredCount)
-    return videos, nil
-}
-func getCaptions(service *youtube.Service, videoID string) ([]CaptionInfo, error) {
-    captionResponse, err := service.Captions.List([]string{"snippet"}, videoID).Do()
-    if err != nil {
-        if strings.Contains(err.Error(), "forbidden") || 
-           strings.Contains(err.Error(), "quotaExceeded") {
-            log.Printf("警告: 動画 %s の字幕取得をスキップ: %v", videoID, err)
-            return []CaptionInfo{}, nil
-        }
-        return nil, fmt.Errorf("字幕情報の取得エラー: %v", err)
-    }
-
-    var captions []CaptionInfo
-    for _, caption := range captionResponse.Items {
-        // 字幕テキストを取得
-		resp, err := service.Captions.Download(caption.Id).Download()
-		if err != nil {
-			log.Printf("Error downloading caption: %v", err)
-			continue
-		}
-		captionTrack, err := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-        captionInfo := CaptionInfo{
-            Language:    caption.Snippet.Language,
-            Text:        string(captionTrack),
-            IsAutomatic: strings.Contains(caption.Snippet.TrackKind, "ASR"),
-        }
-        captions = append(captions, captionInfo)
+    if newest == nil {
+        return nil
     }
-
-    return captions, nil
+    return &ChannelCursor{LastPublishedAt: newest.PublishedAt, LastVideoID: newest.VideoID}
 }
 
 func checkDuplicateInNotion(client *notionapi.Client, databaseID, videoID string) (bool, error) {
@@ -403,10 +408,14 @@ func checkDuplicateInNotion(client *notionapi.Client, databaseID, videoID string
     return len(result.Results) > 0, nil
 }
 
-func saveToNotionWithRetry(client *notionapi.Client, databaseID string, video VideoInfo, maxRetries int) error {
+func saveToNotionWithRetry(client *notionapi.Client, databaseID string, video VideoInfo, tags []string, maxRetries int) error {
+    // progress は Create がどこまで成功したかをリトライ間で引き継ぐ。
+    // これがないと、Create は成功したが後続の AppendChildren が失敗した
+    // ケースで、リトライのたびに新しいページが作られてしまう。
+    var progress notionblocks.CreateProgress
     var lastErr error
     for i := 0; i < maxRetries; i++ {
-        err := saveToNotion(client, databaseID, video)
+        err := saveToNotion(client, databaseID, video, tags, &progress)
         if err == nil {
             return nil
         }
@@ -417,114 +426,20 @@ func saveToNotionWithRetry(client *notionapi.Client, databaseID string, video Vi
 }
 
 
-func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo) error {
+func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo, tags []string, progress *notionblocks.CreateProgress) error {
     description := truncateDescription(video.Description)
 
-	// ブロックの作成
-	blocks := []notionapi.Block{
-		&notionapi.Heading2Block{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeHeading2,
-			},
-			Heading2: notionapi.Heading{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: "要約",
-						},
-					},
-				},
-			},
-		},
-		&notionapi.ParagraphBlock{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeParagraph,
-			},
-			Paragraph: notionapi.Paragraph{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: video.Summary,
-						},
-					},
-				},
-			},
-		},
-		&notionapi.Heading2Block{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeHeading2,
-			},
-			Heading2: notionapi.Heading{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: "動画説明",
-						},
-					},
-				},
-			},
-		},
-		&notionapi.ParagraphBlock{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeParagraph,
-			},
-			Paragraph: notionapi.Paragraph{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: description,
-						},
-					},
-				},
-			},
-		},
-		&notionapi.Heading2Block{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeHeading2,
-			},
-			Heading2: notionapi.Heading{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: "字幕",
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// 字幕ブロックの追加
-	for _, caption := range video.Captions {
-		blocks = append(blocks, &notionapi.ParagraphBlock{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeParagraph,
-			},
-			Paragraph: notionapi.Paragraph{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: fmt.Sprintf("言語: %s\n%s", caption.Language, caption.Text),
-						},
-					},
-				},
-			},
-		})
-	}
-	
-	
+    // rich_text 1要素あたり2000文字、ページ作成時の children は100件までという
+    // Notion APIの上限があるため、要約・説明・字幕はすべて notionblocks で分割
+    // してから、100件を超える分は createPageWithBlocks が AppendChildren で追記する。
+    var blocks []notionapi.Block
+    blocks = append(blocks, notionblocks.Heading2Block("要約"))
+    blocks = append(blocks, notionblocks.ParagraphBlocks(video.Summary)...)
+    blocks = append(blocks, notionblocks.Heading2Block("動画説明"))
+    blocks = append(blocks, notionblocks.ParagraphBlocks(description)...)
+    blocks = append(blocks, notionblocks.Heading2Block("字幕"))
+    blocks = append(blocks, transcriptBlocks(video)...)
+
     params := &notionapi.PageCreateRequest{
         Parent: notionapi.Parent{
             Type:       notionapi.ParentTypeDatabaseID,
@@ -550,82 +465,71 @@ func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo)
                     },
                 },
             },
+            "Tags": notionapi.MultiSelectProperty{
+                MultiSelect: tagOptions(tags),
+            },
         },
-        Children: blocks,
     }
 
-    _, err := client.Page.Create(context.Background(), params)
-    return err
+    return notionblocks.CreatePageWithBlocks(context.Background(), client.Page, client.Block, params, blocks, progress)
 }
 
-// Gemini APIを使用して動画内容を要約する関数（genaiパッケージ版）
-func summarizeWithGemini(apiKey string, video VideoInfo) (string, error) {
-    ctx := context.Background()
-    // APIキーは環境変数 GEMINI_API_KEY から自動で取得される
-    client, err := genai.NewClient(ctx)
-    if err != nil {
-        return "", fmt.Errorf("Geminiクライアントの作成エラー: %v", err)
+// transcriptBlocks は字幕トラックごとにブロックを組み立てる。yt-dlp由来で
+// Cues（発話ごとのタイムスタンプ）がある場合は notionblocks.TranscriptBlocks
+// で "[mm:ss]" の動画ジャンプリンク付き段落にし、Data API経由でタイムスタンプ
+// が無い場合は従来通り言語ラベル付きの段落として分割する。
+func transcriptBlocks(video VideoInfo) []notionapi.Block {
+    var blocks []notionapi.Block
+    for _, caption := range video.Captions {
+        if len(caption.Cues) > 0 {
+            blocks = append(blocks, notionblocks.TranscriptBlocks(video.VideoID, caption.Cues)...)
+            continue
+        }
+        blocks = append(blocks, notionblocks.ParagraphBlocks(fmt.Sprintf("言語: %s\n%s", caption.Language, caption.Text))...)
     }
-    defer client.Close()
-
-    // 要約用のプロンプトを作成
-    prompt := fmt.Sprintf(`以下のYouTube動画の内容を日本語で要約してください。
-
-動画タイトル: %s
-動画説明: %s
+    return blocks
+}
 
-字幕内容:
-`, video.Title, video.Description)
+// tagOptions はchannels.jsonのtagsをNotionのマルチセレクトの選択肢に変換する。
+func tagOptions(tags []string) []notionapi.Option {
+    options := make([]notionapi.Option, len(tags))
+    for i, tag := range tags {
+        options[i] = notionapi.Option{Name: tag}
+    }
+    return options
+}
 
-    // 字幕を追加（最初の日本語字幕または自動生成字幕を使用）
-    var captionText string
-    for _, caption := range video.Captions {
-        if caption.Language == "ja" || caption.Language == "ja-JP" {
-            captionText = caption.Text
-            break
-        }
+// Gemini APIを使用して動画内容を要約する関数。長い書き起こしを8000文字で
+// 切り捨てていた旧実装を、summarizer パッケージによる区間ごとのmap-reduce
+// 要約に置き換え、長尺の動画でも内容を落とさず要約する。promptOverride は
+// channels.jsonのsummary_prompt_templateで、空文字ならデフォルトの指示を使う。
+func summarizeWithGemini(apiKey string, video VideoInfo, captionText, promptOverride string) (string, error) {
+    summary, err := summarizer.NewFromEnv(apiKey).Summarize(
+        context.Background(), video.Title, video.Description, captionText, promptOverride)
+    if err != nil {
+        return "", err
     }
-    if captionText == "" {
-        for _, caption := range video.Captions {
-            if !caption.IsAutomatic {
-                captionText = caption.Text
-                break
+    log.Printf("要約完了: %s (%d文字)", video.Title, len(summary))
+    return summary, nil
+}
+
+// selectCaptionText は languages の優先順位で字幕を選び、どれにも一致しな
+// ければ手動字幕、それも無ければ最初の字幕トラックのテキストを返す。
+func selectCaptionText(captions []CaptionInfo, languages []string) string {
+    for _, lang := range languages {
+        for _, caption := range captions {
+            if caption.Language == lang {
+                return caption.Text
             }
         }
     }
-    if captionText == "" && len(video.Captions) > 0 {
-        captionText = video.Captions[0].Text
-    }
-    if captionText != "" {
-        if len(captionText) > 8000 {
-            captionText = captionText[:8000] + "..."
+    for _, caption := range captions {
+        if !caption.IsAutomatic {
+            return caption.Text
         }
-        prompt += captionText
-    } else {
-        prompt += "字幕は利用できません。"
     }
-    prompt += `
-
-以下の形式で要約してください：
-1. 動画の主要なポイント（3-5個）
-2. 重要な発言や引用
-3. 結論やまとめ
-
-要約は500文字以内で簡潔にまとめてください。`
-
-    model := client.GenerativeModel("gemini-pro")
-    resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-    if err != nil {
-        return "", fmt.Errorf("Gemini APIリクエストエラー: %v", err)
-    }
-    if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-        return "", fmt.Errorf("Gemini APIからの有効なレスポンスがありません")
+    if len(captions) > 0 {
+        return captions[0].Text
     }
-    // パートの内容を文字列として取得
-    var summary string
-    for _, part := range resp.Candidates[0].Content.Parts {
-        summary += fmt.Sprint(part)
-    }
-    log.Printf("要約完了: %s (%d文字)", video.Title, len(summary))
-    return summary, nil
+    return ""
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorFromSuccesses_StopsAtFirstFailure(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	videos := []VideoInfo{
+		{VideoID: "a", PublishedAt: t0},
+		{VideoID: "b", PublishedAt: t0.Add(time.Hour)},
+		{VideoID: "c", PublishedAt: t0.Add(2 * time.Hour)},
+	}
+	successes := []bool{true, false, true}
+
+	cursor := cursorFromSuccesses(videos, successes)
+	if cursor == nil {
+		t.Fatal("expected a non-nil cursor")
+	}
+	if cursor.LastVideoID != "a" || !cursor.LastPublishedAt.Equal(t0) {
+		t.Fatalf("expected cursor to stop at video a, got %+v", cursor)
+	}
+}
+
+func TestCursorFromSuccesses_AllSucceedAdvancesToNewest(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	videos := []VideoInfo{
+		{VideoID: "a", PublishedAt: t0},
+		{VideoID: "b", PublishedAt: t0.Add(time.Hour)},
+	}
+	successes := []bool{true, true}
+
+	cursor := cursorFromSuccesses(videos, successes)
+	if cursor == nil || cursor.LastVideoID != "b" {
+		t.Fatalf("expected cursor to advance to video b, got %+v", cursor)
+	}
+}
+
+func TestCursorFromSuccesses_FirstFailureKeepsCursorNil(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	videos := []VideoInfo{
+		{VideoID: "a", PublishedAt: t0},
+	}
+	successes := []bool{false}
+
+	if cursor := cursorFromSuccesses(videos, successes); cursor != nil {
+		t.Fatalf("expected nil cursor, got %+v", cursor)
+	}
+}
+
+func TestCursorFromSuccesses_NoVideosReturnsNil(t *testing.T) {
+	if cursor := cursorFromSuccesses(nil, nil); cursor != nil {
+		t.Fatalf("expected nil cursor, got %+v", cursor)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	syncStateFile = "sync_state.json"
+	// 初回実行時（カーソルが存在しない時）に遡る期間。
+	initialBackfillWindow = 7 * 24 * time.Hour
+)
+
+// ChannelCursor はチャンネル1件分の最終同期位置。次回実行時はここより後に
+// 公開された動画だけを対象にする。
+type ChannelCursor struct {
+	LastPublishedAt time.Time `json:"last_published_at"`
+	LastVideoID     string    `json:"last_video_id"`
+}
+
+// SyncState はチャンネルごとの最終同期位置を保持する。「今日公開された動画」
+// という日付ベースのフィルタだと、cronの実行漏れで動画が永久に取りこぼされて
+// しまうため、カーソルで「前回どこまで処理したか」を記録する。
+type SyncState struct {
+	Channels map[string]ChannelCursor `json:"channels"`
+}
+
+// loadSyncState は状態ファイルを読み込む。存在しない場合は空の状態を返す。
+func loadSyncState(path string) (*SyncState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &SyncState{Channels: map[string]ChannelCursor{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("同期状態ファイルの読み込みエラー: %v", err)
+	}
+
+	var state SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("同期状態ファイルの解析エラー: %v", err)
+	}
+	if state.Channels == nil {
+		state.Channels = map[string]ChannelCursor{}
+	}
+	return &state, nil
+}
+
+// saveSyncState は状態ファイルを書き込む。
+func saveSyncState(path string, state *SyncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("同期状態のエンコードエラー: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
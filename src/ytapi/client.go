@@ -0,0 +1,182 @@
+// Package ytapi は、YouTube へのアクセス手段（OAuth、APIキー、スクレイパー）
+// を一つの型に集約し、クォータ切れや403を検知して自動的に下位のトランスポート
+// にフォールバックする。
+package ytapi
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+// Client は複数のトランスポートを順番に試す YouTube アクセスクライアント。
+// OAuth経由のサービスアカウント/ユーザー認証が最優先で、クォータ切れ・403を
+// 検知すると APIキーのみのクライアントに、それも尽きるとスクレイパー
+// （RSSフィード + yt-dlp）に自動的に降格する。
+type Client struct {
+	oauth   *youtube.Service // nilなら未設定
+	apiKey  *youtube.Service // nilなら未設定
+	scraper *scraper
+}
+
+// NewClient は利用可能なトランスポートを登録した Client を作る。
+// oauth / apiKey はどちらか一方が nil でもよい（その場合はそのトランスポートを飛ばす）。
+func NewClient(oauth, apiKey *youtube.Service, ipPool IPPool) *Client {
+	return &Client{
+		oauth:   oauth,
+		apiKey:  apiKey,
+		scraper: newScraper(ipPool),
+	}
+}
+
+// LatestVideoIDFromRSS はチャンネルの最新動画IDを返す。Data APIを消費しない
+// ため、常にスクレイパー（RSSフィード）経由で取得する。
+func (c *Client) LatestVideoIDFromRSS(ctx context.Context, channelID string) (string, error) {
+	return c.scraper.LatestVideoIDFromRSS(ctx, channelID)
+}
+
+// VideosInChannel は since より後に公開された動画を返す。
+// OAuth -> APIキー -> スクレイパー の順に試し、クォータ切れ/403を見たら
+// 次のトランスポートに降格する。
+func (c *Client) VideosInChannel(ctx context.Context, channelID string, since time.Time) ([]VideoInfo, error) {
+	if c.oauth != nil {
+		videos, err := videosInChannelViaAPI(ctx, c.oauth, channelID, since)
+		if err == nil {
+			return videos, nil
+		}
+		if !isQuotaOrForbidden(err) {
+			return nil, err
+		}
+		log.Printf("警告: OAuth経由でのチャンネル取得がクォータ切れ/403のため、APIキー経由にフォールバックします: %v", err)
+	}
+
+	if c.apiKey != nil {
+		videos, err := videosInChannelViaAPI(ctx, c.apiKey, channelID, since)
+		if err == nil {
+			return videos, nil
+		}
+		if !isQuotaOrForbidden(err) {
+			return nil, err
+		}
+		log.Printf("警告: APIキー経由でのチャンネル取得もクォータ切れ/403のため、スクレイパーにフォールバックします: %v", err)
+	}
+
+	return c.scraper.VideosInChannel(ctx, channelID, since)
+}
+
+// CaptionsForVideo は動画の字幕を取得する。OAuth経由は所有者アップロード字幕
+// にしか使えないことが多いため、失敗時や字幕0件時はスクレイパー（yt-dlp）に
+// 降格する。環境変数 USE_YTDLP=1 が設定されている場合は、OAuth/APIキーの
+// 資格情報が無い環境向けに最初からスクレイパーのみを使う。
+func (c *Client) CaptionsForVideo(ctx context.Context, videoID string) ([]CaptionInfo, error) {
+	if useYtdlpByDefault() {
+		return c.scraper.CaptionsForVideo(ctx, videoID)
+	}
+
+	if c.oauth != nil {
+		captions, err := captionsViaAPI(ctx, c.oauth, videoID)
+		if err == nil && len(captions) > 0 {
+			return captions, nil
+		}
+		if err != nil && !isQuotaOrForbidden(err) {
+			return nil, err
+		}
+	}
+
+	if c.apiKey != nil {
+		captions, err := captionsViaAPI(ctx, c.apiKey, videoID)
+		if err == nil && len(captions) > 0 {
+			return captions, nil
+		}
+		if err != nil && !isQuotaOrForbidden(err) {
+			return nil, err
+		}
+	}
+
+	return c.scraper.CaptionsForVideo(ctx, videoID)
+}
+
+func videosInChannelViaAPI(ctx context.Context, service *youtube.Service, channelID string, since time.Time) ([]VideoInfo, error) {
+	channelResponse, err := service.Channels.List([]string{"contentDetails"}).Id(channelID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(channelResponse.Items) == 0 {
+		return nil, errChannelNotFound(channelID)
+	}
+	uploadsPlaylistID := channelResponse.Items[0].ContentDetails.RelatedPlaylists.Uploads
+
+	var videos []VideoInfo
+	nextPageToken := ""
+
+pageLoop:
+	for {
+		call := service.PlaylistItems.List([]string{"snippet"}).
+			PlaylistId(uploadsPlaylistID).
+			MaxResults(50).
+			Context(ctx)
+		if nextPageToken != "" {
+			call = call.PageToken(nextPageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Items {
+			publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+			if err != nil {
+				continue
+			}
+			if !publishedAt.After(since) {
+				break pageLoop
+			}
+			videos = append(videos, VideoInfo{
+				VideoID:      item.Snippet.ResourceId.VideoId,
+				Title:        item.Snippet.Title,
+				Description:  item.Snippet.Description,
+				PublishedAt:  publishedAt,
+				ChannelTitle: item.Snippet.ChannelTitle,
+				URL:          "https://www.youtube.com/watch?v=" + item.Snippet.ResourceId.VideoId,
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		nextPageToken = resp.NextPageToken
+	}
+
+	if err := attachDurations(ctx, service, videos); err != nil {
+		log.Printf("警告: チャンネル %s の動画時間取得に失敗（min_duration/skip_shortsは無効になります）: %v", channelID, err)
+	}
+
+	return videos, nil
+}
+
+func captionsViaAPI(ctx context.Context, service *youtube.Service, videoID string) ([]CaptionInfo, error) {
+	resp, err := service.Captions.List([]string{"snippet"}, videoID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	var captions []CaptionInfo
+	for _, item := range resp.Items {
+		dl, err := service.Captions.Download(item.Id).Download()
+		if err != nil {
+			continue
+		}
+		text, err := readAllAndClose(dl.Body)
+		if err != nil {
+			continue
+		}
+		captions = append(captions, CaptionInfo{
+			Language:    item.Snippet.Language,
+			Text:        text,
+			IsAutomatic: item.Snippet.TrackKind == "ASR",
+		})
+	}
+	return captions, nil
+}
@@ -0,0 +1,59 @@
+package ytapi
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+var iso8601DurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// parseISO8601Duration は、YouTubeが contentDetails.duration で返す
+// ISO 8601形式の時間（例: "PT5M30S"）をパースする。マッチしなければ0を返す。
+func parseISO8601Duration(raw string) time.Duration {
+	match := iso8601DurationPattern.FindStringSubmatch(raw)
+	if match == nil {
+		return 0
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+}
+
+const videosListBatchSize = 50
+
+// attachDurations は Videos.List(contentDetails) を使って各動画の
+// Duration フィールドを埋める。Data APIは1リクエストで受け付けるID数に
+// 上限があるため、videosListBatchSize 件ずつに分けて呼び出す。
+func attachDurations(ctx context.Context, service *youtube.Service, videos []VideoInfo) error {
+	durations := make(map[string]time.Duration, len(videos))
+
+	for start := 0; start < len(videos); start += videosListBatchSize {
+		end := start + videosListBatchSize
+		if end > len(videos) {
+			end = len(videos)
+		}
+		ids := make([]string, end-start)
+		for i, v := range videos[start:end] {
+			ids[i] = v.VideoID
+		}
+
+		resp, err := service.Videos.List([]string{"contentDetails"}).Id(strings.Join(ids, ",")).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			durations[item.Id] = parseISO8601Duration(item.ContentDetails.Duration)
+		}
+	}
+
+	for i, v := range videos {
+		videos[i].Duration = durations[v.VideoID]
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package ytapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// isQuotaOrForbidden は、OAuth/APIキー経由のトランスポートを諦めて
+// スクレイパーにフォールバックすべきエラーかどうかを判定する。
+func isQuotaOrForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusForbidden || apiErr.Code == http.StatusTooManyRequests {
+			return true
+		}
+		return false
+	}
+
+	// 古い文字列ベースのエラー（サービスアカウント経由など）にもフォールバックする。
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "quotaexceeded") || strings.Contains(msg, "forbidden")
+}
@@ -0,0 +1,19 @@
+package ytapi
+
+import (
+	"fmt"
+	"io"
+)
+
+func errChannelNotFound(channelID string) error {
+	return fmt.Errorf("チャンネルが見つかりません: %s", channelID)
+}
+
+func readAllAndClose(r io.ReadCloser) (string, error) {
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,54 @@
+package ytapi
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// IPPool は、スクレイパー経由のリクエスト（yt-dlp, timedtext等）を分散させる
+// ための送信元（プロキシ）をローテーションさせる。同じアドレスから大量に
+// アクセスし続けるとレート制限やブロックを受けやすいため、外部ytsyncの
+// ip_manager と同様に複数の出口を順番に使い回す。
+type IPPool interface {
+	// Next は次に使うべきプロキシURL（例: "socks5://127.0.0.1:9050"）を返す。
+	// プールが空の場合は空文字列を返し、呼び出し側はプロキシなしで続行する。
+	Next() string
+}
+
+// staticPool は環境変数で指定された固定リストをラウンドロビンで返す実装。
+type staticPool struct {
+	mu        sync.Mutex
+	proxies   []string
+	nextIndex int
+}
+
+// NewIPPoolFromEnv は YTAPI_PROXY_LIST（カンマ区切りのプロキシURL）から
+// IPPool を組み立てる。未設定の場合は常に空文字列を返す no-op プールになる。
+func NewIPPoolFromEnv() IPPool {
+	raw := os.Getenv("YTAPI_PROXY_LIST")
+	if raw == "" {
+		return &staticPool{}
+	}
+
+	var proxies []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return &staticPool{proxies: proxies}
+}
+
+func (p *staticPool) Next() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.proxies) == 0 {
+		return ""
+	}
+	proxy := p.proxies[p.nextIndex%len(p.proxies)]
+	p.nextIndex++
+	return proxy
+}
@@ -0,0 +1,123 @@
+package ytapi
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// scraper は YouTube Data API を使わず、公開ページ/フィードと yt-dlp から
+// メタデータと字幕を取得するフォールバック経路。OAuth/APIキーどちらの
+// トランスポートもクォータ切れ・403になったときに使う。
+type scraper struct {
+	httpClient *http.Client
+	ipPool     IPPool
+}
+
+func newScraper(ipPool IPPool) *scraper {
+	return &scraper{httpClient: http.DefaultClient, ipPool: ipPool}
+}
+
+// rssFeed は YouTube の公開アップロードフィードのXML構造。
+type rssFeed struct {
+	Entries []rssEntry `xml:"entry"`
+}
+
+type rssEntry struct {
+	VideoID   string `xml:"videoId"`
+	Title     string `xml:"title"`
+	Published string `xml:"published"`
+}
+
+func (s *scraper) httpGet(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.httpClient
+	// IPPoolから払い出されたプロキシをこのリクエストだけに適用する。RSS/公開ページの
+	// 取得もyt-dlpの字幕取得と同じ送信元に偏らせないため、ここでも必ずローテーション
+	// から引いて使う。
+	if proxy := s.ipPool.Next(); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("プロキシURLの解析エラー: %v", err)
+		}
+		client = &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// LatestVideoIDFromRSS はチャンネルの公開アップロードフィードから最新動画IDを取得する。
+func (s *scraper) LatestVideoIDFromRSS(ctx context.Context, channelID string) (string, error) {
+	feedURL := fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", url.QueryEscape(channelID))
+	body, err := s.httpGet(ctx, feedURL)
+	if err != nil {
+		return "", fmt.Errorf("RSSフィードの取得エラー: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return "", fmt.Errorf("RSSフィードの解析エラー: %v", err)
+	}
+	if len(feed.Entries) == 0 {
+		return "", fmt.Errorf("RSSフィードに動画が見つかりません: %s", channelID)
+	}
+	return feed.Entries[0].VideoID, nil
+}
+
+// VideosInChannel はRSSフィードに載っている分（直近15件程度）から
+// since より後の動画を返す。RSSはData APIと違って全履歴は取れないため、
+// バックフィルにはOAuth/APIキー経路の方が向く。あくまで最終フォールバック。
+func (s *scraper) VideosInChannel(ctx context.Context, channelID string, since time.Time) ([]VideoInfo, error) {
+	feedURL := fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", url.QueryEscape(channelID))
+	body, err := s.httpGet(ctx, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("RSSフィードの取得エラー: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("RSSフィードの解析エラー: %v", err)
+	}
+
+	var videos []VideoInfo
+	for _, entry := range feed.Entries {
+		publishedAt, err := time.Parse(time.RFC3339, entry.Published)
+		if err != nil {
+			continue
+		}
+		if !publishedAt.After(since) {
+			continue
+		}
+		videos = append(videos, VideoInfo{
+			VideoID:     entry.VideoID,
+			Title:       entry.Title,
+			PublishedAt: publishedAt,
+			URL:         fmt.Sprintf("https://www.youtube.com/watch?v=%s", entry.VideoID),
+		})
+	}
+	return videos, nil
+}
+
+// CaptionsForVideo は yt-dlp を使って自動生成字幕を含む字幕を取得する。
+// --proxy には IPPool がローテーションで払い出したプロキシを渡し、
+// 同一の送信元に偏らないようにする。
+func (s *scraper) CaptionsForVideo(ctx context.Context, videoID string) ([]CaptionInfo, error) {
+	_, captions, err := fetchViaYtdlp(ctx, videoID, s.ipPool.Next())
+	if err != nil {
+		return nil, err
+	}
+	return captions, nil
+}
@@ -0,0 +1,33 @@
+package ytapi
+
+import "time"
+
+// VideoInfo は動画1件分のメタデータ。
+type VideoInfo struct {
+	VideoID      string
+	Title        string
+	Description  string
+	PublishedAt  time.Time
+	ChannelTitle string
+	URL          string
+	// Duration はData API経由（videosInChannelViaAPI）でのみ埋まる。
+	// RSS/yt-dlpスクレイパー経由の結果では常にゼロ値になる。
+	Duration time.Duration
+}
+
+// CaptionInfo は字幕トラック1件分の情報。
+type CaptionInfo struct {
+	Language    string
+	Text        string
+	IsAutomatic bool
+	// Cues は発話単位のタイムスタンプ付き字幕。VTT/SRTから取得した場合のみ
+	// 埋まり、Data API経由（captionsViaAPI）の結果では常に空になる。
+	Cues []Cue
+}
+
+// Cue は字幕1キュー分のテキストと、それが話され始めた動画中の時刻。
+// notionblocks パッケージが "[mm:ss]" の動画ジャンプリンクを作る際に使う。
+type Cue struct {
+	Start time.Duration
+	Text  string
+}
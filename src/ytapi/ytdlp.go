@@ -0,0 +1,237 @@
+package ytapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSubtitleLangs は YTDLP_SUBTITLE_LANGS 未設定時に使う yt-dlp の
+// --sub-lang の値。
+const defaultSubtitleLangs = "ja,ja-JP,en"
+
+const ytdlpMaxRetries = 3
+
+// YtdlThumbnail は yt-dlp の -J メタデータに含まれるサムネイル1件分。
+type YtdlThumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// YtdlChapter は yt-dlp の -J メタデータに含まれるチャプター1件分。
+type YtdlChapter struct {
+	Title     string  `json:"title"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+}
+
+// YtdlVideo は yt-dlp の -J メタデータのうち、Notion同期で使う部分だけを
+// 抜き出したもの。
+type YtdlVideo struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	UploadDate  string          `json:"upload_date"`
+	Tags        []string        `json:"tags"`
+	Categories  []string        `json:"categories"`
+	Duration    float64         `json:"duration"`
+	Thumbnails  []YtdlThumbnail `json:"thumbnails"`
+	Chapters    []YtdlChapter   `json:"chapters"`
+}
+
+// subtitleLanguages は yt-dlp の --sub-lang に渡す値を返す。
+// YTDLP_SUBTITLE_LANGS で上書きすれば、デプロイごとに優先言語を変えられる。
+func subtitleLanguages() string {
+	if langs := os.Getenv("YTDLP_SUBTITLE_LANGS"); langs != "" {
+		return langs
+	}
+	return defaultSubtitleLangs
+}
+
+// useYtdlpByDefault は、OAuth/サービスアカウントの認証情報を何も設定して
+// いないデプロイ向けに、失敗後ではなくData APIより先にyt-dlpを試すべきかを返す。
+func useYtdlpByDefault() bool {
+	return os.Getenv("USE_YTDLP") == "1"
+}
+
+// fetchViaYtdlp は --skip-download 付きでyt-dlpを1動画に対して実行し、
+// -J メタデータを VideoInfo にパースした上で、書き出された字幕サイドカー
+// ファイルを読み戻す。OAuthもAPIキーも不要なため、どちらも設定していない
+// ユーザーでも動作し続ける。
+func fetchViaYtdlp(ctx context.Context, videoID, proxy string) (VideoInfo, []CaptionInfo, error) {
+	tmpDir, err := os.MkdirTemp("", "ytapi-ytdlp-")
+	if err != nil {
+		return VideoInfo{}, nil, fmt.Errorf("一時ディレクトリの作成に失敗: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	watchURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+	outputTemplate := filepath.Join(tmpDir, "%(id)s.%(ext)s")
+
+	var raw []byte
+	var lastErr error
+	for attempt := 0; attempt < ytdlpMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		args := []string{
+			"-J",
+			"--write-auto-sub", "--write-sub", "--sub-lang", subtitleLanguages(),
+			"--skip-download",
+			"-o", outputTemplate,
+		}
+		if proxy != "" {
+			args = append(args, "--proxy", proxy)
+		}
+		args = append(args, watchURL)
+
+		out, err := exec.CommandContext(ctx, "yt-dlp", args...).Output()
+		if err == nil {
+			raw = out
+			lastErr = nil
+			break
+		}
+		lastErr = err
+		log.Printf("警告: yt-dlp呼び出し失敗 (動画 %s, 試行 %d/%d): %v", videoID, attempt+1, ytdlpMaxRetries, err)
+	}
+	if lastErr != nil {
+		return VideoInfo{}, nil, fmt.Errorf("yt-dlpでの取得に失敗しました: %v", lastErr)
+	}
+
+	var meta YtdlVideo
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return VideoInfo{}, nil, fmt.Errorf("yt-dlpメタデータの解析エラー: %v", err)
+	}
+
+	publishedAt, err := time.Parse("20060102", meta.UploadDate)
+	if err != nil {
+		publishedAt = time.Time{}
+	}
+
+	video := VideoInfo{
+		VideoID:     meta.ID,
+		Title:       meta.Title,
+		Description: meta.Description,
+		PublishedAt: publishedAt,
+		URL:         watchURL,
+	}
+
+	captions, err := readYtdlpSubtitles(tmpDir, meta.ID)
+	if err != nil {
+		log.Printf("警告: 動画 %s の字幕ファイル読み込みに失敗: %v", videoID, err)
+	}
+
+	return video, captions, nil
+}
+
+// subtitleFilePattern は yt-dlp のサイドカー字幕ファイル名（例:
+// "VIDEOID.ja.vtt" や "VIDEOID.en.srt"）にマッチし、言語タグを取り出す。
+var subtitleFilePattern = regexp.MustCompile(`\.([a-zA-Z-]+)\.(vtt|srt)$`)
+
+func readYtdlpSubtitles(dir, videoID string) ([]CaptionInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var captions []CaptionInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), videoID+".") {
+			continue
+		}
+		match := subtitleFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Printf("警告: 字幕ファイル %s の読み込みに失敗: %v", entry.Name(), err)
+			continue
+		}
+
+		cues := cuesFromSubtitle(string(data))
+		captions = append(captions, CaptionInfo{
+			Language: match[1],
+			Text:     cuesText(cues),
+			// yt-dlpは自動生成字幕と手動字幕を同じファイル名パターンで
+			// 書き出すため、サイドカーだけからは確実に区別できない。この
+			// フォールバックに到達する第三者の動画はほぼ自動字幕しか
+			// 持たないため、常に自動生成扱いとする。
+			IsAutomatic: true,
+			Cues:        cues,
+		})
+	}
+	return captions, nil
+}
+
+var (
+	vttTimestampLine = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})[.,](\d{3})\s*-->\s*\d{2}:\d{2}:\d{2}[.,]\d{3}`)
+	vttTagPattern    = regexp.MustCompile(`<[^>]+>`)
+	srtIndexLine     = regexp.MustCompile(`^\d+$`)
+)
+
+// cuesFromSubtitle は VTT/SRT ファイルをタイムスタンプ付きのキューに変換する。
+// キュー番号やインラインタグを取り除き、自動生成字幕が重複するウィンドウで
+// 繰り返しがちな連続した重複行はまとめる。最初のタイムスタンプより前に出てくる
+// 行（"WEBVTT" などのヘッダー）は時刻に紐付けられないため捨てる。
+func cuesFromSubtitle(raw string) []Cue {
+	var cues []Cue
+	var last string
+	var start time.Duration
+	haveStart := false
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "WEBVTT" || srtIndexLine.MatchString(line) ||
+			strings.HasPrefix(line, "Kind:") || strings.HasPrefix(line, "Language:") {
+			continue
+		}
+		if m := vttTimestampLine.FindStringSubmatch(line); m != nil {
+			start = timestampToDuration(m[1], m[2], m[3], m[4])
+			haveStart = true
+			continue
+		}
+		if !haveStart {
+			continue
+		}
+
+		line = vttTagPattern.ReplaceAllString(line, "")
+		if line == "" || line == last {
+			continue
+		}
+		cues = append(cues, Cue{Start: start, Text: line})
+		last = line
+	}
+	return cues
+}
+
+func timestampToDuration(hours, minutes, seconds, millis string) time.Duration {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	ms, _ := strconv.Atoi(millis)
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+}
+
+// cuesText は キューを summarizer や selectCaptionText が読む1本の字幕文字列に
+// 平坦化する。タイムスタンプが意味を持つのは notionblocks 側であり、要約では
+// 使わない。
+func cuesText(cues []Cue) string {
+	texts := make([]string, len(cues))
+	for i, c := range cues {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, " ")
+}
@@ -1,13 +1,13 @@
 package main
 
 import (
-	"io"
     "context"
     "fmt"
     "log"
     "os"
     "time"
     "strings"
+    "sort"
     "sync"
     "github.com/jomei/notionapi"
 	"google.golang.org/api/option"
@@ -16,14 +16,54 @@ import (
 	"encoding/json"
     "golang.org/x/oauth2"
     "golang.org/x/oauth2/google"
+    "github.com/kkaiki/youtube_summary_to_notion/internal/ytapi"
+    "github.com/kkaiki/youtube_summary_to_notion/internal/summarizer"
+    "github.com/kkaiki/youtube_summary_to_notion/internal/translate"
 )
 
 const (
     MaxDescriptionLength = 2000
-    YouTubeScope = youtube.YoutubeReadonlyScope + " " + 
+    YouTubeScope = youtube.YoutubeReadonlyScope + " " +
                   youtube.YoutubeForceSslScope
+    syncStateFile = "sync_state.json"
+    // 初回実行時に遡る期間。これより古い動画は対象外とする。
+    initialBackfillWindow = 7 * 24 * time.Hour
 )
 
+// SyncState はチャンネルごとの最終同期位置を保持する。
+type SyncState struct {
+    Channels map[string]time.Time `json:"channels"`
+}
+
+// loadSyncState は状態ファイルを読み込む。存在しない場合は空の状態を返す。
+func loadSyncState(path string) (*SyncState, error) {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return &SyncState{Channels: map[string]time.Time{}}, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("同期状態ファイルの読み込みエラー: %v", err)
+    }
+
+    var state SyncState
+    if err := json.Unmarshal(data, &state); err != nil {
+        return nil, fmt.Errorf("同期状態ファイルの解析エラー: %v", err)
+    }
+    if state.Channels == nil {
+        state.Channels = map[string]time.Time{}
+    }
+    return &state, nil
+}
+
+// saveSyncState は状態ファイルを書き込む。
+func saveSyncState(path string, state *SyncState) error {
+    data, err := json.MarshalIndent(state, "", "  ")
+    if err != nil {
+        return fmt.Errorf("同期状態のエンコードエラー: %v", err)
+    }
+    return os.WriteFile(path, data, 0644)
+}
+
 type VideoInfo struct {
     VideoID      string
     Title        string
@@ -32,12 +72,40 @@ type VideoInfo struct {
     ChannelTitle string
     URL          string
     Captions     []CaptionInfo
+    Summary      *summarizer.Summary // ENABLE_SUMMARIZATION=1 のときのみ設定される
 }
 
 type CaptionInfo struct {
-    Language    string
-    Text        string
-    IsAutomatic bool
+    Language         string
+    Text             string
+    IsAutomatic      bool
+    Source           string // "api" または "timedtext"（取得経路。Notion表示用）
+    DetectedLanguage string // whatlanggoによる実際の言語推定(ISO 639-1)
+    TranslatedFrom   string // 翻訳で生成された場合、翻訳元言語(ISO 639-1)。空ならオリジナル。
+    // Cues は timedtext 経由で取得できた場合のキュー単位のタイミング情報。
+    // これがあることで、要約生成時にLLMが章立てのタイムスタンプを
+    // 字幕から実際に読み取れる（ないと推測するしかなくなる）。
+    Cues []ytapi.Cue
+}
+
+// concatCaptionText は要約に渡すために、全字幕トラックの本文を連結する。
+// Cues が取得できているトラックは "[mm:ss] テキスト" の形式でキューごとに
+// 行を分け、LLMが章立てのタイムスタンプを実際の字幕から読み取れるようにする。
+// Cues がない（captions.download経由や翻訳済みの）トラックは従来通り本文のみ。
+func concatCaptionText(captions []CaptionInfo) string {
+    var texts []string
+    for _, c := range captions {
+        if len(c.Cues) == 0 {
+            texts = append(texts, c.Text)
+            continue
+        }
+        lines := make([]string, len(c.Cues))
+        for i, cue := range c.Cues {
+            lines[i] = fmt.Sprintf("[%02d:%02d] %s", int(cue.Start.Minutes()), int(cue.Start.Seconds())%60, cue.Text)
+        }
+        texts = append(texts, strings.Join(lines, "\n"))
+    }
+    return strings.Join(texts, "\n")
 }
 
 // 説明文を制限する関数
@@ -71,6 +139,32 @@ func main() {
         log.Fatalf("YouTubeサービスの作成に失敗: %v", err)
     }
 
+    // クォータ計測・レート制限付きのYouTube APIクライアントを初期化
+    ytClient, err := ytapi.NewClient(youtubeService)
+    if err != nil {
+        log.Fatalf("ytapiクライアントの作成に失敗: %v", err)
+    }
+
+    // 要約機能（ENABLE_SUMMARIZATION=1）。未設定の場合は従来通り字幕を生のまま書き込む。
+    var videoSummarizer summarizer.Summarizer
+    if summarizer.Enabled() {
+        videoSummarizer, err = summarizer.NewFromEnv()
+        if err != nil {
+            log.Fatalf("summarizerの作成に失敗: %v", err)
+        }
+    }
+
+    // 翻訳機能（TRANSLATE_PROVIDER設定時のみ有効）。PREFERRED_LANGUAGES以外の
+    // 言語で検出された字幕を翻訳して別トラックとして追加する。
+    var translator translate.Translator
+    if os.Getenv("TRANSLATE_PROVIDER") != "" {
+        translator, err = translate.NewFromEnv()
+        if err != nil {
+            log.Fatalf("translatorの作成に失敗: %v", err)
+        }
+    }
+    preferredLanguages := translate.PreferredLanguages()
+
     // Notionクライアントの初期化
     notionClient := notionapi.NewClient(notionapi.Token(notionAPIKey))
 
@@ -81,11 +175,35 @@ func main() {
         "UCXjTiSGclQLVVU83GVrRM4w", // ホリエモン
     }
 
+    // 前回の同期位置を読み込む
+    state, err := loadSyncState(syncStateFile)
+    if err != nil {
+        log.Fatalf("同期状態の読み込みに失敗: %v", err)
+    }
+
     // チャンネルごとの処理
     for _, channelID := range channelIDs {
-        processChannel(ctx, youtubeService, notionClient, channelID, notionDatabaseID)
-    }
+        since, ok := state.Channels[channelID]
+        if !ok {
+            since = time.Now().Add(-initialBackfillWindow)
+        }
 
+        latest, err := processChannel(ctx, ytClient, notionClient, videoSummarizer, translator, preferredLanguages, channelID, notionDatabaseID, since)
+        if err != nil {
+            if ytapi.IsQuotaExceeded(err) {
+                log.Printf("クォータ上限に達したため、残りのチャンネルの処理を打ち切ります: %v", err)
+                break
+            }
+            log.Printf("エラー: チャンネル %s の処理に失敗: %v", channelID, err)
+            continue
+        }
+        if latest.After(since) {
+            state.Channels[channelID] = latest
+            if err := saveSyncState(syncStateFile, state); err != nil {
+                log.Printf("エラー: 同期状態の保存に失敗: %v", err)
+            }
+        }
+    }
 }
 
 // getServiceAccountClient関数の修正
@@ -169,18 +287,48 @@ func getClient(config *oauth2.Config) *http.Client {
     return config.Client(context.Background(), tok)
 }
 // processChannel 関数の修正
-func processChannel(ctx context.Context, youtubeService *youtube.Service, notionClient *notionapi.Client, channelID, databaseID string) {
-    
-    videos, err := getLatestVideos(youtubeService, channelID)
+// since より後に公開された動画だけを処理し、実際に処理できた動画のうち
+// 最も新しい PublishedAt を返す（呼び出し側がカーソルを前進させるために使う）。
+func processChannel(ctx context.Context, ytClient *ytapi.Client, notionClient *notionapi.Client, videoSummarizer summarizer.Summarizer, translator translate.Translator, preferredLanguages []string, channelID, databaseID string, since time.Time) (time.Time, error) {
+
+    apiVideos, err := ytClient.VideosInChannel(ctx, channelID, since)
     if err != nil {
-        log.Printf("エラー: チャンネル %s の動画取得に失敗: %v", channelID, err)
-        return
+        if ytapi.IsQuotaExceeded(err) {
+            return since, err
+        }
+        return since, fmt.Errorf("チャンネル %s の動画取得に失敗: %v", channelID, err)
+    }
+    log.Printf("チャンネル %s から %d 件の動画を取得しました (since %s)", channelID, len(apiVideos), since.Format(time.RFC3339))
+
+    videos := make([]VideoInfo, len(apiVideos))
+    for i, v := range apiVideos {
+        videos[i] = VideoInfo{
+            VideoID:      v.VideoID,
+            Title:        v.Title,
+            Description:  truncateDescription(v.Description),
+            PublishedAt:  v.PublishedAt,
+            ChannelTitle: v.ChannelTitle,
+            URL:          v.URL,
+        }
     }
-    log.Printf("チャンネル %s から %d 件の動画を取得しました", channelID, len(videos))
 
     var wg sync.WaitGroup
+    var mu sync.Mutex
     semaphore := make(chan struct{}, 3)
 
+    // 各動画の処理結果（成功したか）を PublishedAt とともに記録し、
+    // wg.Wait() の後にまとめてカーソルを決定する。
+    type videoResult struct {
+        publishedAt time.Time
+        success     bool
+    }
+    results := make([]videoResult, 0, len(videos))
+    recordResult := func(v VideoInfo, success bool) {
+        mu.Lock()
+        results = append(results, videoResult{publishedAt: v.PublishedAt, success: success})
+        mu.Unlock()
+    }
+
     for _, video := range videos {
         wg.Add(1)
         go func(v VideoInfo) {
@@ -192,147 +340,230 @@ func processChannel(ctx context.Context, youtubeService *youtube.Service, notion
             exists, err := checkDuplicateInNotion(notionClient, databaseID, v.VideoID)
             if err != nil {
                 log.Printf("エラー: 重複チェック中 (VideoID: %s): %v", v.VideoID, err)
+                recordResult(v, false)
                 return
             }
             if exists {
                 log.Printf("スキップ: 動画 %s は既にNotionに存在します", v.VideoID)
+                recordResult(v, true)
                 return
             }
 
-            captions, err := getCaptions(youtubeService, v.VideoID)
+            apiCaptions, err := ytClient.CaptionsForVideo(ctx, v.VideoID)
             if err != nil {
                 log.Printf("警告: 動画 %s の字幕取得に失敗: %v", v.VideoID, err)
             } else {
-                log.Printf("字幕取得完了: %s (%d 件の字幕)", v.VideoID, len(captions))
+                log.Printf("字幕取得完了: %s (%d 件の字幕)", v.VideoID, len(apiCaptions))
+            }
+            for _, c := range apiCaptions {
+                caption := CaptionInfo{
+                    Language:         c.Language,
+                    Text:             c.Text,
+                    IsAutomatic:      c.IsAutomatic,
+                    Source:           string(c.Source),
+                    DetectedLanguage: c.DetectedLanguage,
+                    Cues:             c.Cues,
+                }
+                v.Captions = append(v.Captions, caption)
+
+                if translator != nil && !translate.IsPreferred(caption.DetectedLanguage, preferredLanguages) {
+                    target := preferredLanguages[0]
+                    translated, err := translator.Translate(ctx, caption.Text, caption.DetectedLanguage, target)
+                    if err != nil {
+                        log.Printf("警告: 動画 %s の字幕翻訳(%s->%s)に失敗: %v", v.VideoID, caption.DetectedLanguage, target, err)
+                    } else {
+                        v.Captions = append(v.Captions, CaptionInfo{
+                            Language:         target,
+                            Text:             translated,
+                            IsAutomatic:      caption.IsAutomatic,
+                            Source:           caption.Source,
+                            DetectedLanguage: target,
+                            TranslatedFrom:   caption.DetectedLanguage,
+                        })
+                    }
+                }
+            }
+
+            if videoSummarizer != nil {
+                summary, err := videoSummarizer.Summarize(ctx, summarizer.Input{
+                    Title:       v.Title,
+                    Description: v.Description,
+                    CaptionText: concatCaptionText(v.Captions),
+                })
+                if err != nil {
+                    log.Printf("警告: 動画 %s の要約生成に失敗: %v", v.VideoID, err)
+                } else {
+                    v.Summary = &summary
+                }
             }
-            v.Captions = captions
 
             err = saveToNotionWithRetry(notionClient, databaseID, v, 3)
             if err != nil {
                 log.Printf("エラー: Notionへの保存失敗 (VideoID: %s): %v", v.VideoID, err)
+                recordResult(v, false)
                 return
             }
+
+            recordResult(v, true)
         }(video)
     }
 
     wg.Wait()
+
+    // 公開日の古い順に見て、最初に失敗した動画より前までしかカーソルを
+    // 進めない。途中の失敗を飛び越えて進めると、次回実行時に
+    // since より古いという理由でその動画が二度と再試行されなくなる。
+    sort.Slice(results, func(i, j int) bool {
+        return results[i].publishedAt.Before(results[j].publishedAt)
+    })
+    latest := since
+    for _, r := range results {
+        if !r.success {
+            break
+        }
+        if r.publishedAt.After(latest) {
+            latest = r.publishedAt
+        }
+    }
+    return latest, nil
 }
 
-// getLatestVideos 関数の修正
-func getLatestVideos(service *youtube.Service, channelID string) ([]VideoInfo, error) {
+func checkDuplicateInNotion(client *notionapi.Client, databaseID, videoID string) (bool, error) {
+    query := &notionapi.DatabaseQueryRequest{
+        Filter: &notionapi.PropertyFilter{
+            Property: "URL",
+            RichText: &notionapi.TextFilterCondition{
+                Contains: videoID,
+            },
+        },
+    }
     
-    channelResponse, err := service.Channels.List([]string{"contentDetails"}).
-        Id(channelID).
-        Do()
+    result, err := client.Database.Query(context.Background(), notionapi.DatabaseID(databaseID), query)
     if err != nil {
-        log.Printf("チャンネル情報取得エラー: %v", err)
-        return nil, err
+        return false, err
     }
-    log.Printf("チャンネル情報取得成功")
+    
+    return len(result.Results) > 0, nil
+}
 
-    if len(channelResponse.Items) == 0 {
-        return nil, fmt.Errorf("チャンネルが見つかりません")
+// summaryBlocks は TL;DR・要点・章立てをNotionブロックに変換する。
+func summaryBlocks(summary summarizer.Summary) []notionapi.Block {
+    blocks := []notionapi.Block{
+        &notionapi.Heading2Block{
+            BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeHeading2},
+            Heading2: notionapi.Heading{
+                RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: "要約"}}},
+            },
+        },
+        &notionapi.ParagraphBlock{
+            BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeParagraph},
+            Paragraph: notionapi.Paragraph{
+                RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: summary.TLDR}}},
+            },
+        },
     }
 
-    uploadsPlaylistID := channelResponse.Items[0].ContentDetails.RelatedPlaylists.Uploads
-    log.Printf("アップロードプレイリストID: %s", uploadsPlaylistID)
-
-    var videos []VideoInfo
-    playlistResponse, err := service.PlaylistItems.List([]string{"snippet"}).
-        PlaylistId(uploadsPlaylistID).
-        MaxResults(50).
-        Do()
-    if err != nil {
-        return nil, fmt.Errorf("プレイリストアイテムの取得に失敗: %v", err)
+    for _, point := range summary.KeyPoints {
+        blocks = append(blocks, &notionapi.BulletedListItemBlock{
+            BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeBulletedListItem},
+            BulletedListItem: notionapi.ListItem{
+                RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: point}}},
+            },
+        })
     }
 
-    now := time.Now().In(time.Local)
-    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
-    yesterday := today.AddDate(0, 0, -1)
-
-    filteredCount := 0
-    for _, item := range playlistResponse.Items {
-        publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
-        if err != nil {
-            log.Printf("警告: 動画 %s の日付解析に失敗: %v", item.Snippet.ResourceId.VideoId, err)
-            continue
-        }
-        
-        publishedAtJST := publishedAt.In(time.Local)
-        publishedDate := time.Date(publishedAtJST.Year(), publishedAtJST.Month(), publishedAtJST.Day(), 0, 0, 0, 0, time.Local)
-
-        if publishedDate.Equal(today) || publishedDate.Equal(yesterday) {
-            video := VideoInfo{
-                VideoID:      item.Snippet.ResourceId.VideoId,
-                Title:        item.Snippet.Title,
-                Description:  truncateDescription(item.Snippet.Description),
-                PublishedAt:  publishedAt,
-                ChannelTitle: item.Snippet.ChannelTitle,
-                URL:         fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.Snippet.ResourceId.VideoId),
-            }
-            videos = append(videos, video)
-            filteredCount++
-            log.Printf("対象動画を追加: %s (%s)", video.Title, video.PublishedAt.Format("2006-01-02 15:04:05"))
+    if len(summary.Chapters) > 0 {
+        blocks = append(blocks, &notionapi.Heading2Block{
+            BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeHeading2},
+            Heading2: notionapi.Heading{
+                RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: "チャプター"}}},
+            },
+        })
+        for _, ch := range summary.Chapters {
+            blocks = append(blocks, &notionapi.BulletedListItemBlock{
+                BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeBulletedListItem},
+                BulletedListItem: notionapi.ListItem{
+                    RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: fmt.Sprintf("[%s] %s", ch.Timestamp, ch.Title)}}},
+                },
+            })
         }
     }
 
-    log.Printf("チャンネル %s から %d 件の対象動画を抽出しました", channelID, filteredCount)
-    return videos, nil
+    return blocks
 }
-func getCaptions(service *youtube.Service, videoID string) ([]CaptionInfo, error) {
-    captionResponse, err := service.Captions.List([]string{"snippet"}, videoID).Do()
-    if err != nil {
-        if strings.Contains(err.Error(), "forbidden") || 
-           strings.Contains(err.Error(), "quotaExceeded") {
-            log.Printf("警告: 動画 %s の字幕取得をスキップ: %v", videoID, err)
-            return []CaptionInfo{}, nil
-        }
-        return nil, fmt.Errorf("字幕情報の取得エラー: %v", err)
+
+// transcriptMaxRunes は、Notionのrich_text 1要素あたりの上限(2000文字)に合わせた
+// トグル内の全文字幕の分割サイズ。
+const transcriptMaxRunes = 2000
+
+// captionHeader は各字幕ブロックの前に付けるラベル行を組み立てる。
+// 翻訳で生成されたトラックには翻訳元言語も添える。
+func captionHeader(caption CaptionInfo) string {
+    if caption.TranslatedFrom != "" {
+        return fmt.Sprintf("言語: %s (取得元: %s, %s からの翻訳)", caption.Language, caption.Source, caption.TranslatedFrom)
     }
+    return fmt.Sprintf("言語: %s (取得元: %s)", caption.Language, caption.Source)
+}
 
-    var captions []CaptionInfo
-    for _, caption := range captionResponse.Items {
-        // 字幕テキストを取得
-		resp, err := service.Captions.Download(caption.Id).Download()
-		if err != nil {
-			log.Printf("Error downloading caption: %v", err)
-			continue
-		}
-		captionTrack, err := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-        captionInfo := CaptionInfo{
-            Language:    caption.Snippet.Language,
-            Text:        string(captionTrack),
-            IsAutomatic: strings.Contains(caption.Snippet.TrackKind, "ASR"),
+// transcriptToggleBlock は全字幕を2000文字以内のパラグラフに分割し、
+// 折りたたみ可能なトグルブロックの子として格納する。
+func transcriptToggleBlock(captions []CaptionInfo) notionapi.Block {
+    var children []notionapi.Block
+    for _, caption := range captions {
+        header := captionHeader(caption)
+        children = append(children, &notionapi.ParagraphBlock{
+            BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeParagraph},
+            Paragraph: notionapi.Paragraph{
+                RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: header}}},
+            },
+        })
+        for _, chunk := range splitIntoRuneChunks(caption.Text, transcriptMaxRunes) {
+            children = append(children, &notionapi.ParagraphBlock{
+                BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeParagraph},
+                Paragraph: notionapi.Paragraph{
+                    RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: chunk}}},
+                },
+            })
         }
-        captions = append(captions, captionInfo)
     }
 
-    return captions, nil
+    return &notionapi.ToggleBlock{
+        BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeToggle},
+        Toggle: notionapi.Toggle{
+            RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: "全文の字幕"}}},
+            Children: children,
+        },
+    }
 }
 
-func checkDuplicateInNotion(client *notionapi.Client, databaseID, videoID string) (bool, error) {
-    query := &notionapi.DatabaseQueryRequest{
-        Filter: &notionapi.PropertyFilter{
-            Property: "URL",
-            RichText: &notionapi.TextFilterCondition{
-                Contains: videoID,
-            },
-        },
+// splitIntoRuneChunks は s を maxRunes 以下のルーン数ごとに分割する。
+func splitIntoRuneChunks(s string, maxRunes int) []string {
+    runes := []rune(s)
+    if len(runes) == 0 {
+        return nil
     }
-    
-    result, err := client.Database.Query(context.Background(), notionapi.DatabaseID(databaseID), query)
-    if err != nil {
-        return false, err
+    var chunks []string
+    for len(runes) > 0 {
+        end := maxRunes
+        if end > len(runes) {
+            end = len(runes)
+        }
+        chunks = append(chunks, string(runes[:end]))
+        runes = runes[end:]
     }
-    
-    return len(result.Results) > 0, nil
+    return chunks
 }
 
 func saveToNotionWithRetry(client *notionapi.Client, databaseID string, video VideoInfo, maxRetries int) error {
+    // progress は Page.Create がどこまで成功したかをリトライ間で引き継ぐ。
+    // これがないと、最初の Create は成功したが後続の AppendChildren で
+    // 失敗したケースで、リトライのたびに新しいページが作られてしまい、
+    // 古いページが空のまま（あるいは途中まで書かれた状態で）Notion上に
+    // 取り残される。
+    var progress createProgress
     var lastErr error
     for i := 0; i < maxRetries; i++ {
-        err := saveToNotion(client, databaseID, video)
+        err := saveToNotion(client, databaseID, video, &progress)
         if err == nil {
             return nil
         }
@@ -343,7 +574,7 @@ func saveToNotionWithRetry(client *notionapi.Client, databaseID string, video Vi
 }
 
 
-func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo) error {
+func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo, progress *createProgress) error {
     description := truncateDescription(video.Description)
 
 	// ブロックの作成
@@ -364,45 +595,75 @@ func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo)
 				},
 			},
 		},
-		&notionapi.Heading2Block{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeHeading2,
-			},
-			Heading2: notionapi.Heading{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: "字幕",
-						},
+	}
+
+	if video.Summary != nil {
+		blocks = append(blocks, summaryBlocks(*video.Summary)...)
+	}
+
+	blocks = append(blocks, &notionapi.Heading2Block{
+		BasicBlock: notionapi.BasicBlock{
+			Object: "block",
+			Type:   notionapi.BlockTypeHeading2,
+		},
+		Heading2: notionapi.Heading{
+			RichText: []notionapi.RichText{
+				{
+					Type: "text",
+					Text: &notionapi.Text{
+						Content: "字幕",
 					},
 				},
 			},
 		},
-	}
-
-	// 字幕ブロックの追加
-	for _, caption := range video.Captions {
-		blocks = append(blocks, &notionapi.ParagraphBlock{
-			BasicBlock: notionapi.BasicBlock{
-				Object: "block",
-				Type:   notionapi.BlockTypeParagraph,
-			},
-			Paragraph: notionapi.Paragraph{
-				RichText: []notionapi.RichText{
-					{
-						Type: "text",
-						Text: &notionapi.Text{
-							Content: fmt.Sprintf("言語: %s\n%s", caption.Language, caption.Text),
+	})
+
+	if video.Summary != nil {
+		// 要約がある場合、全文は折りたたみトグルの中に収めて、ページの見通しを保つ。
+		blocks = append(blocks, transcriptToggleBlock(video.Captions))
+	} else {
+		// 要約なしの場合は従来通り、字幕を段落として並べる。
+		// rich_text.text.content は2000文字を超えられないため、caption.Text は
+		// splitIntoRuneChunks で分割し、複数の段落ブロックに収める。
+		for _, caption := range video.Captions {
+			header := captionHeader(caption)
+			blocks = append(blocks, &notionapi.ParagraphBlock{
+				BasicBlock: notionapi.BasicBlock{
+					Object: "block",
+					Type:   notionapi.BlockTypeParagraph,
+				},
+				Paragraph: notionapi.Paragraph{
+					RichText: []notionapi.RichText{
+						{
+							Type: "text",
+							Text: &notionapi.Text{
+								Content: header,
+							},
 						},
 					},
 				},
-			},
-		})
+			})
+			for _, chunk := range splitIntoRuneChunks(caption.Text, transcriptMaxRunes) {
+				blocks = append(blocks, &notionapi.ParagraphBlock{
+					BasicBlock: notionapi.BasicBlock{
+						Object: "block",
+						Type:   notionapi.BlockTypeParagraph,
+					},
+					Paragraph: notionapi.Paragraph{
+						RichText: []notionapi.RichText{
+							{
+								Type: "text",
+								Text: &notionapi.Text{
+									Content: chunk,
+								},
+							},
+						},
+					},
+				})
+			}
+		}
 	}
-	
-	
+
     params := &notionapi.PageCreateRequest{
         Parent: notionapi.Parent{
             Type:       notionapi.ParentTypeDatabaseID,
@@ -429,9 +690,69 @@ func saveToNotion(client *notionapi.Client, databaseID string, video VideoInfo)
                 },
             },
         },
-        Children: blocks,
     }
 
-    _, err := client.Page.Create(context.Background(), params)
-    return err
+    return createPageWithBlocks(context.Background(), client.Page, client.Block, params, blocks, progress)
+}
+
+// maxChildrenPerRequest は、ページ作成・AppendChildren いずれのリクエストでも
+// Notion APIが一度に受け付ける children の上限。
+const maxChildrenPerRequest = 100
+
+// pageCreator と blockAppender は notionapi.Client の該当サービスが満たす
+// 最小限のインターフェース。テストではフェイク実装に差し替えられる。
+type pageCreator interface {
+    Create(ctx context.Context, request *notionapi.PageCreateRequest) (*notionapi.Page, error)
+}
+
+type blockAppender interface {
+    AppendChildren(ctx context.Context, blockID notionapi.BlockID, request *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error)
+}
+
+// createProgress は createPageWithBlocks がリトライを跨いで引き継ぐ状態。
+// ゼロ値は「まだ何も作成していない」を表す。
+type createProgress struct {
+    pageID   notionapi.ObjectID
+    appended int // rest のうち AppendChildren 済みの件数
+}
+
+// createPageWithBlocks は、100件を超えるブロックを持つページを安全に作成する。
+// 最初の100件だけでページを作成し、残りは AppendChildren をバッチ呼び出しして
+// 追記する。caption の並び順は呼び出し元が組み立てた blocks の順序のまま保たれる。
+// progress に既にページIDが入っていれば Create はスキップし、前回 AppendChildren
+// が成功した続きから再開する。呼び出し元は同じ *createProgress をリトライ間で
+// 使い回すことで、途中失敗のたびにページが重複作成されるのを防ぐ。
+func createPageWithBlocks(ctx context.Context, pages pageCreator, blocks blockAppender, params *notionapi.PageCreateRequest, allBlocks []notionapi.Block, progress *createProgress) error {
+    firstBatch, rest := allBlocks, []notionapi.Block(nil)
+    if len(allBlocks) > maxChildrenPerRequest {
+        firstBatch = allBlocks[:maxChildrenPerRequest]
+        rest = allBlocks[maxChildrenPerRequest:]
+    }
+
+    if progress.pageID == "" {
+        params.Children = firstBatch
+        page, err := pages.Create(ctx, params)
+        if err != nil {
+            return err
+        }
+        progress.pageID = page.ID
+    }
+
+    rest = rest[progress.appended:]
+    for len(rest) > 0 {
+        batch := rest
+        if len(batch) > maxChildrenPerRequest {
+            batch = batch[:maxChildrenPerRequest]
+        }
+        _, err := blocks.AppendChildren(ctx, notionapi.BlockID(progress.pageID), &notionapi.AppendBlockChildrenRequest{
+            Children: batch,
+        })
+        if err != nil {
+            return fmt.Errorf("ブロックの追記に失敗 (残り%d件): %v", len(rest), err)
+        }
+        progress.appended += len(batch)
+        rest = rest[len(batch):]
+    }
+
+    return nil
 }
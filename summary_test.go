@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/kkaiki/youtube_summary_to_notion/internal/ytapi"
+)
+
+// fakeNotionClient は createPageWithBlocks の呼び出しを記録するだけの
+// フェイク実装。pageCreator / blockAppender の両方を満たす。
+type fakeNotionClient struct {
+	createdChildren []notionapi.Block
+	appendedBatches [][]notionapi.Block
+}
+
+func (f *fakeNotionClient) Create(ctx context.Context, request *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+	f.createdChildren = request.Children
+	return &notionapi.Page{ID: "fake-page-id"}, nil
+}
+
+func (f *fakeNotionClient) AppendChildren(ctx context.Context, blockID notionapi.BlockID, request *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+	f.appendedBatches = append(f.appendedBatches, request.Children)
+	return &notionapi.AppendBlockChildrenResponse{}, nil
+}
+
+func paragraphBlock(content string) notionapi.Block {
+	return &notionapi.ParagraphBlock{
+		BasicBlock: notionapi.BasicBlock{Object: "block", Type: notionapi.BlockTypeParagraph},
+		Paragraph: notionapi.Paragraph{
+			RichText: []notionapi.RichText{{Type: "text", Text: &notionapi.Text{Content: content}}},
+		},
+	}
+}
+
+func blockContent(t *testing.T, b notionapi.Block) string {
+	t.Helper()
+	p, ok := b.(*notionapi.ParagraphBlock)
+	if !ok {
+		t.Fatalf("expected *notionapi.ParagraphBlock, got %T", b)
+	}
+	return p.Paragraph.RichText[0].Text.Content
+}
+
+func TestCreatePageWithBlocks_FitsInSingleCreate(t *testing.T) {
+	fake := &fakeNotionClient{}
+	blocks := []notionapi.Block{paragraphBlock("1"), paragraphBlock("2"), paragraphBlock("3")}
+
+	if err := createPageWithBlocks(context.Background(), fake, fake, &notionapi.PageCreateRequest{}, blocks, &createProgress{}); err != nil {
+		t.Fatalf("createPageWithBlocks returned error: %v", err)
+	}
+
+	if len(fake.createdChildren) != 3 {
+		t.Fatalf("expected all 3 blocks in the create call, got %d", len(fake.createdChildren))
+	}
+	if len(fake.appendedBatches) != 0 {
+		t.Fatalf("expected no AppendChildren calls, got %d", len(fake.appendedBatches))
+	}
+}
+
+func TestCreatePageWithBlocks_SplitsAcross100BlockBoundary(t *testing.T) {
+	fake := &fakeNotionClient{}
+	const total = 250
+	blocks := make([]notionapi.Block, total)
+	for i := 0; i < total; i++ {
+		blocks[i] = paragraphBlock(string(rune('a' + i%26)))
+	}
+
+	if err := createPageWithBlocks(context.Background(), fake, fake, &notionapi.PageCreateRequest{}, blocks, &createProgress{}); err != nil {
+		t.Fatalf("createPageWithBlocks returned error: %v", err)
+	}
+
+	if len(fake.createdChildren) != maxChildrenPerRequest {
+		t.Fatalf("expected create call to carry exactly %d blocks, got %d", maxChildrenPerRequest, len(fake.createdChildren))
+	}
+	if len(fake.appendedBatches) != 2 {
+		t.Fatalf("expected 2 AppendChildren batches for the remaining 150 blocks, got %d", len(fake.appendedBatches))
+	}
+	if len(fake.appendedBatches[0]) != maxChildrenPerRequest {
+		t.Fatalf("expected first append batch to carry %d blocks, got %d", maxChildrenPerRequest, len(fake.appendedBatches[0]))
+	}
+	if len(fake.appendedBatches[1]) != total-2*maxChildrenPerRequest {
+		t.Fatalf("expected second append batch to carry %d blocks, got %d", total-2*maxChildrenPerRequest, len(fake.appendedBatches[1]))
+	}
+
+	// すべてのブロックを作成順のまま連結し、caption の並び順が保たれているか確認する。
+	var reassembled []notionapi.Block
+	reassembled = append(reassembled, fake.createdChildren...)
+	for _, batch := range fake.appendedBatches {
+		reassembled = append(reassembled, batch...)
+	}
+	if len(reassembled) != total {
+		t.Fatalf("expected %d blocks in total after reassembly, got %d", total, len(reassembled))
+	}
+	for i, b := range blocks {
+		if blockContent(t, reassembled[i]) != blockContent(t, b) {
+			t.Fatalf("block order not preserved at index %d", i)
+		}
+	}
+}
+
+// failingAppendClient は AppendChildren を指定回数まで失敗させ、それ以降は
+// fakeNotionClient と同じ挙動にフォールバックする。リトライ時に Create が
+// 再実行されないこと、AppendChildren が前回の続きから再開することを確認する。
+type failingAppendClient struct {
+	fakeNotionClient
+	failAppendsRemaining int
+}
+
+func (f *failingAppendClient) AppendChildren(ctx context.Context, blockID notionapi.BlockID, request *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+	if f.failAppendsRemaining > 0 {
+		f.failAppendsRemaining--
+		return nil, fmt.Errorf("simulated append failure")
+	}
+	return f.fakeNotionClient.AppendChildren(ctx, blockID, request)
+}
+
+func TestCreatePageWithBlocks_ResumesAfterAppendFailureWithoutRecreating(t *testing.T) {
+	fake := &failingAppendClient{failAppendsRemaining: 1}
+	const total = 250
+	blocks := make([]notionapi.Block, total)
+	for i := 0; i < total; i++ {
+		blocks[i] = paragraphBlock(string(rune('a' + i%26)))
+	}
+
+	var progress createProgress
+	if err := createPageWithBlocks(context.Background(), fake, fake, &notionapi.PageCreateRequest{}, blocks, &progress); err == nil {
+		t.Fatalf("expected the first attempt to fail")
+	}
+	if len(fake.createdChildren) != maxChildrenPerRequest {
+		t.Fatalf("expected page to be created once with %d blocks, got %d", maxChildrenPerRequest, len(fake.createdChildren))
+	}
+	if len(fake.appendedBatches) != 0 {
+		t.Fatalf("expected no successful append batches yet, got %d", len(fake.appendedBatches))
+	}
+
+	if err := createPageWithBlocks(context.Background(), fake, fake, &notionapi.PageCreateRequest{}, blocks, &progress); err != nil {
+		t.Fatalf("createPageWithBlocks returned error on retry: %v", err)
+	}
+	if len(fake.createdChildren) != maxChildrenPerRequest {
+		t.Fatalf("expected Create not to be called again, still %d blocks", maxChildrenPerRequest)
+	}
+	if len(fake.appendedBatches) != 2 {
+		t.Fatalf("expected 2 AppendChildren batches for the remaining 150 blocks, got %d", len(fake.appendedBatches))
+	}
+}
+
+func TestConcatCaptionText_IncludesTimestampsWhenCuesPresent(t *testing.T) {
+	captions := []CaptionInfo{
+		{
+			Text: "こんにちは 今日は天気がいいですね",
+			Cues: []ytapi.Cue{
+				{Start: 0, Text: "こんにちは"},
+				{Start: 65 * time.Second, Text: "今日は天気がいいですね"},
+			},
+		},
+		{
+			Text: "字幕なしトラック", // Cues が無い（captions.download経由など）
+		},
+	}
+
+	got := concatCaptionText(captions)
+	want := "[00:00] こんにちは\n[01:05] 今日は天気がいいですね\n字幕なしトラック"
+	if got != want {
+		t.Fatalf("concatCaptionText() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitIntoRuneChunks(t *testing.T) {
+	text := ""
+	for i := 0; i < 4500; i++ {
+		text += "a"
+	}
+
+	chunks := splitIntoRuneChunks(text, transcriptMaxRunes)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks for 4500 runes at 2000/chunk, got %d", len(chunks))
+	}
+	if len([]rune(chunks[0])) != transcriptMaxRunes || len([]rune(chunks[1])) != transcriptMaxRunes {
+		t.Fatalf("expected the first two chunks to be exactly %d runes", transcriptMaxRunes)
+	}
+	if len([]rune(chunks[2])) != 500 {
+		t.Fatalf("expected the final chunk to carry the remaining 500 runes, got %d", len([]rune(chunks[2])))
+	}
+}
+
+func TestSplitIntoRuneChunks_Empty(t *testing.T) {
+	if chunks := splitIntoRuneChunks("", transcriptMaxRunes); chunks != nil {
+		t.Fatalf("expected nil for empty input, got %v", chunks)
+	}
+}